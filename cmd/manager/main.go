@@ -29,9 +29,12 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
 	"github.com/tmax-cloud/l2c-operator/internal"
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
 	"github.com/tmax-cloud/l2c-operator/pkg/apis"
 	"github.com/tmax-cloud/l2c-operator/pkg/apiserver"
+	"github.com/tmax-cloud/l2c-operator/pkg/apiserver/webhook"
 	"github.com/tmax-cloud/l2c-operator/pkg/controller"
+	tupwascontroller "github.com/tmax-cloud/l2c-operator/pkg/controller/tupwas"
 	"github.com/tmax-cloud/l2c-operator/version"
 )
 
@@ -63,14 +66,21 @@ func main() {
 	pflag.StringVar(&internal.StorageClassName, "storageClassName", "csi-cephfs-sc", "storage class name for PVC to be created")
 	pflag.StringVar(&internal.EncryptKey, "encryptKey", "l2c-operator-salt-12333", "Encryption key for storing password")
 	pflag.StringVar(&internal.IngressClass, "ingressClass", "nginx-shd", "Ingress class")
+	pflag.StringVar(&internal.IngressHostTemplate, "ingressHostTemplate", internal.DefaultIngressHostTemplate, "Go template of ingress hosts, with .Prefix, .Name, .Namespace and .Address (IP or hostname of the load balancer)")
+	pflag.StringVar(&internal.ExposureApi, "exposureApi", "", fmt.Sprintf("API to expose WAS/IDE (%s, %s or %s), detected from the cluster if empty", internal.ExposureApiIngressV1, internal.ExposureApiIngressV1beta1, internal.ExposureApiRoute))
+	pflag.StringVar(&internal.TlsCaSecret, "tlsCaSecret", "", "CA secret (kubernetes.io/tls type, <namespace>/<name>) to issue certificates for ingresses, ingresses are served over plain HTTP if empty")
 
 	pflag.StringVar(&internal.EditorImage, "editorImage", fmt.Sprintf("tmaxcloudck/l2c-vscode:%s", version.Version), "image url of web ide")
-
-	pflag.StringVar(&internal.BuilderImageJeus7, "builderImageJeus7", "tmaxcloudck/s2i-jeus:8", "Builder image for JEUS7 WAS") // TODO - Jeus7 builder image
-	pflag.StringVar(&internal.BuilderImageJeus8, "builderImageJeus8", "tmaxcloudck/s2i-jeus:8", "Builder image for JEUS8 WAS")
+	pflag.StringVar(&internal.SummaryImage, "summaryImage", "python:3.8-alpine", "image url (having python3) to summarize the analysis result, e.g., a mirrored one for air-gapped clusters")
 
 	pflag.StringVar(&internal.WasProjectStorageSize, "wasProjectStorageSize", "1Gi", "Storage size for was project size (including git project/analyze result)")
 
+	// Kept for the existing deployments, builder images are configured by TupBuilderProfiles
+	pflag.StringVar(&internal.BuilderImageJeus7, "builderImageJeus7", "", "Builder image for JEUS7 WAS, set to jeus-7 TupBuilderProfile")
+	pflag.StringVar(&internal.BuilderImageJeus8, "builderImageJeus8", "", "Builder image for JEUS8 WAS, set to jeus-8 TupBuilderProfile")
+	_ = pflag.CommandLine.MarkDeprecated("builderImageJeus7", "use TupBuilderProfile jeus-7 instead")
+	_ = pflag.CommandLine.MarkDeprecated("builderImageJeus8", "use TupBuilderProfile jeus-8 instead")
+
 	pflag.Parse()
 
 	// Use a zap logr.Logger implementation. If none of the zap
@@ -132,6 +142,10 @@ func main() {
 	extServer := apiserver.New()
 	go extServer.Start()
 
+	// Start git webhook server, exposed through an ingress
+	webhookServer := webhook.New()
+	go webhookServer.Start()
+
 	log.Info("Registering Components.")
 
 	// Setup Scheme for all resources
@@ -148,6 +162,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Seed or override the default TupBuilderProfiles with the deprecated builder image flags
+	if err := tupwascontroller.SeedBuilderProfiles(cfg, mgr.GetScheme()); err != nil {
+		log.Error(err, "")
+		os.Exit(1)
+	}
+
+	// Detect the API to expose WAS/IDE, before setting up the watches
+	if internal.ExposureApi == "" {
+		exposureApi, err := utils.DetectExposureApi(cfg)
+		if err != nil {
+			log.Error(err, "")
+			os.Exit(1)
+		}
+		internal.ExposureApi = exposureApi
+	}
+	log.Info(fmt.Sprintf("Exposing WAS/IDE by %s", internal.ExposureApi))
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
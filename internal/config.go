@@ -4,13 +4,26 @@ var (
 	EditorImage      string
 	StorageClassName string
 
+	// Image of the step summarizing the analysis result, having python3
+	SummaryImage string
+
 	EncryptKey   string
 	IngressClass string
 
+	// Template of ingress hosts, which can be overridden by each TupWAS
+	IngressHostTemplate string
+
+	// API of the objects exposing WAS/IDE outside the cluster (ExposureApi*), detected at startup if not given
+	ExposureApi string
+
+	// CA secret (namespace/name) to issue certificates for ingresses
+	TlsCaSecret string
+
 	WasProjectStorageSize string
 )
 
-// Builder images
+// Builder images of JEUS7/JEUS8
+// Deprecated: use TupBuilderProfiles instead, they only seed or override the builder images of the default profiles
 var (
 	BuilderImageJeus7 string
 	BuilderImageJeus8 string
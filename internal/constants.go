@@ -4,3 +4,21 @@ const (
 	APIServiceName = "v1.tup.tmax.io"
 	ServiceName    = "l2c-operator"
 )
+
+const (
+	// Prefix is one of was/ide/report/config, Address is the IP or hostname of the load balancer of the ingress
+	// WAS host has no prefix by default, as it had before the template is introduced
+	DefaultIngressHostTemplate = `{{if ne .Prefix "was"}}{{.Prefix}}.{{end}}{{.Name}}.{{.Namespace}}.{{.Address}}.nip.io`
+)
+
+const (
+	// APIs of the objects exposing WAS/IDE outside the cluster
+	ExposureApiIngressV1      = "networking.k8s.io/v1"
+	ExposureApiIngressV1beta1 = "networking.k8s.io/v1beta1"
+	ExposureApiRoute          = "route.openshift.io/v1"
+)
+
+const (
+	// Hash of the desired object last applied by the operator, to detect changes of TupWAS/TupDB spec or operator config
+	AnnotationKeySpecHash = "tmax.io/spec-hash"
+)
@@ -3,6 +3,7 @@ package utils
 import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
 	authorization "k8s.io/client-go/kubernetes/typed/authorization/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -32,6 +33,18 @@ func AuthClient() (*authorization.AuthorizationV1Client, error) {
 	return c, nil
 }
 
+func KubeClient() (*kubernetes.Clientset, error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	c, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func AddSchemes(opt *client.Options, gv schema.GroupVersion, types ...runtime.Object) {
 	if opt.Scheme == nil {
 		opt.Scheme = runtime.NewScheme()
@@ -2,14 +2,25 @@ package utils
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"reflect"
 
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
@@ -43,6 +54,40 @@ func ApiServiceName() string {
 	return svcName
 }
 
+// DetectExposureApi finds the API to expose WAS/IDE outside the cluster, among the ones served by the cluster
+// Routes are preferred on OpenShift, then networking.k8s.io/v1 Ingresses
+func DetectExposureApi(cfg *rest.Config) (string, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return "", err
+	}
+
+	candidates := []struct {
+		api      string
+		resource string
+	}{
+		{api: internal.ExposureApiRoute, resource: "routes"},
+		{api: internal.ExposureApiIngressV1, resource: "ingresses"},
+		{api: internal.ExposureApiIngressV1beta1, resource: "ingresses"},
+	}
+	for _, c := range candidates {
+		resources, err := dc.ServerResourcesForGroupVersion(c.api)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == c.resource {
+				return c.api, nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf("none of %s, %s and %s is served", internal.ExposureApiRoute, internal.ExposureApiIngressV1, internal.ExposureApiIngressV1beta1)
+}
+
 func CheckAndCreateObject(obj interface{}, parent metav1.Object, c client.Client, scheme *runtime.Scheme, deleteFirst bool) error {
 	metaObj, isMetaObj := obj.(metav1.Object)
 	if !isMetaObj {
@@ -89,3 +134,307 @@ func CheckAndCreateObject(obj interface{}, parent metav1.Object, c client.Client
 
 	return nil
 }
+
+// CreateObject creates the object without checking if it exists, e.g., an object with generateName
+func CreateObject(obj interface{}, parent metav1.Object, c client.Client, scheme *runtime.Scheme) error {
+	metaObj, isMetaObj := obj.(metav1.Object)
+	if !isMetaObj {
+		return fmt.Errorf("given object is not a meta object")
+	}
+
+	// First set ownerReference
+	if parent != nil {
+		if err := controllerutil.SetControllerReference(parent, metaObj, scheme); err != nil {
+			return fmt.Errorf("ownerRef: %s", err.Error())
+		}
+	}
+
+	// Cast to runtime object
+	runtimeObj, isRuntimeObj := metaObj.(runtime.Object)
+	if !isRuntimeObj {
+		return fmt.Errorf("given object is not a runtime object")
+	}
+
+	// Now create
+	if err := c.Create(context.TODO(), runtimeObj); err != nil {
+		return fmt.Errorf("create: %s", err.Error())
+	}
+
+	return nil
+}
+
+// DeleteObjectsWithLabels deletes objects of the list types (e.g., &corev1.ServiceList{}) having the labels in the namespace
+// It tries to delete all of them, and returns the errors aggregated
+func DeleteObjectsWithLabels(c client.Client, namespace string, labels map[string]string, lists ...runtime.Object) error {
+	var errs []error
+	for _, list := range lists {
+		if err := c.List(context.TODO(), list, client.InNamespace(namespace), client.MatchingLabels(labels)); err != nil {
+			errs = append(errs, fmt.Errorf("list: %s", err.Error()))
+			continue
+		}
+		objs, err := meta.ExtractList(list)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, obj := range objs {
+			// Pods of deployments are also deleted in background
+			if err := c.Delete(context.TODO(), obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+				name := ""
+				if metaObj, isMetaObj := obj.(metav1.Object); isMetaObj {
+					name = metaObj.GetName()
+				}
+				errs = append(errs, fmt.Errorf("delete %s: %s", name, err.Error()))
+			}
+		}
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// CheckAndUpdateObject creates the object, or updates it if the desired object is changed since it was applied last time
+// or the fields set in the desired object are changed by others (drift)
+// Desired objects are compared by the hash annotation, and the live fields are compared only if they are set in the desired
+// object, so the fields set by the server or other controllers are kept
+// Top-level fields updated by the operator after the object is applied (e.g., spec of the exposures) are given as ignoredFields,
+// not to be compared with the live object
+// Mutable fields are patched (JSON merge patch), and the object is recreated only if a known immutable field is changed
+func CheckAndUpdateObject(obj interface{}, parent metav1.Object, c client.Client, scheme *runtime.Scheme, ignoredFields ...string) error {
+	metaObj, isMetaObj := obj.(metav1.Object)
+	if !isMetaObj {
+		return fmt.Errorf("given object is not a meta object")
+	}
+	runtimeObj, isRuntimeObj := metaObj.(runtime.Object)
+	if !isRuntimeObj {
+		return fmt.Errorf("given object is not a runtime object")
+	}
+
+	// First set ownerReference, as it is also a part of the desired object
+	if parent != nil {
+		if err := controllerutil.SetControllerReference(parent, metaObj, scheme); err != nil {
+			return fmt.Errorf("ownerRef: %s", err.Error())
+		}
+	}
+
+	patch, err := desiredPatch(runtimeObj)
+	if err != nil {
+		return fmt.Errorf("patch: %s", err.Error())
+	}
+	hash, err := objectHash(patch)
+	if err != nil {
+		return fmt.Errorf("hash: %s", err.Error())
+	}
+	annotations := metaObj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[internal.AnnotationKeySpecHash] = hash
+	metaObj.SetAnnotations(annotations)
+	patchMeta, _ := patch["metadata"].(map[string]interface{})
+	patchMeta["annotations"] = annotations
+
+	currentObj := runtimeObj.DeepCopyObject()
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: metaObj.GetName(), Namespace: metaObj.GetNamespace()}, currentObj); err != nil {
+		if errors.IsNotFound(err) {
+			if err := c.Create(context.TODO(), runtimeObj); err != nil {
+				return fmt.Errorf("create: %s", err.Error())
+			}
+			return nil
+		}
+		return fmt.Errorf("get: %s", err.Error())
+	}
+	currentMeta, isMetaObj := currentObj.(metav1.Object)
+	if !isMetaObj {
+		return fmt.Errorf("given object is not a meta object")
+	}
+	if currentMeta.GetAnnotations()[internal.AnnotationKeySpecHash] == hash {
+		currentContent, err := objectContent(currentObj)
+		if err != nil {
+			return fmt.Errorf("get: %s", err.Error())
+		}
+		livePatch := map[string]interface{}{}
+		for k, v := range patch {
+			livePatch[k] = v
+		}
+		for _, f := range ignoredFields {
+			delete(livePatch, f)
+		}
+		if fieldsMatch(livePatch, currentContent) {
+			return nil
+		}
+	}
+
+	// Immutable field is changed, recreate it (ownerReference is already set)
+	if immutableFieldChanged(runtimeObj, currentObj) {
+		if err := CheckAndCreateObject(obj, nil, c, scheme, true); err != nil {
+			return fmt.Errorf("recreate: %s", err.Error())
+		}
+		return nil
+	}
+
+	// Fields allocated by the server, which would be dropped by replacing the lists, are kept
+	if svc, isSvc := runtimeObj.(*corev1.Service); isSvc {
+		if currentSvc, isSvc := currentObj.(*corev1.Service); isSvc {
+			desiredSvc := svc.DeepCopy()
+			keepNodePorts(desiredSvc, currentSvc)
+			patch, err = desiredPatch(desiredSvc)
+			if err != nil {
+				return fmt.Errorf("patch: %s", err.Error())
+			}
+		}
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return fmt.Errorf("patch: %s", err.Error())
+	}
+	if err := c.Patch(context.TODO(), currentObj, client.ConstantPatch(types.MergePatchType, data)); err != nil {
+		return fmt.Errorf("patch: %s", err.Error())
+	}
+
+	return nil
+}
+
+// Whether the desired object changes any known immutable field of the current object
+// - Service: spec.clusterIP
+// - Deployment: spec.selector
+// - Secret: type
+func immutableFieldChanged(desired, current runtime.Object) bool {
+	switch d := desired.(type) {
+	case *corev1.Secret:
+		cur, isSecret := current.(*corev1.Secret)
+		return isSecret && d.Type != "" && d.Type != cur.Type
+	case *corev1.Service:
+		cur, isSvc := current.(*corev1.Service)
+		return isSvc && d.Spec.ClusterIP != "" && d.Spec.ClusterIP != cur.Spec.ClusterIP
+	case *appsv1.Deployment:
+		cur, isDeploy := current.(*appsv1.Deployment)
+		return isDeploy && !equality.Semantic.DeepEqual(d.Spec.Selector, cur.Spec.Selector)
+	}
+	return false
+}
+
+// Copy the nodePorts allocated to the current service ports (matched by port and protocol) to the desired ones
+func keepNodePorts(desired, current *corev1.Service) {
+	if desired.Spec.Type != corev1.ServiceTypeNodePort && desired.Spec.Type != corev1.ServiceTypeLoadBalancer {
+		return
+	}
+	for i, port := range desired.Spec.Ports {
+		if port.NodePort != 0 {
+			continue
+		}
+		for _, cur := range current.Spec.Ports {
+			if cur.Port == port.Port && serviceProtocol(cur.Protocol) == serviceProtocol(port.Protocol) {
+				desired.Spec.Ports[i].NodePort = cur.NodePort
+				break
+			}
+		}
+	}
+}
+
+// Fields of the object set by the operator - labels, annotations, ownerReferences and the others except status
+func desiredPatch(obj runtime.Object) (map[string]interface{}, error) {
+	content, err := objectContent(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	patch := map[string]interface{}{}
+	for k, v := range content {
+		switch k {
+		case "apiVersion", "kind", "metadata", "status":
+			continue
+		}
+		patch[k] = v
+	}
+
+	metadata := map[string]interface{}{}
+	objMeta, _ := content["metadata"].(map[string]interface{})
+	for _, k := range []string{"labels", "annotations", "ownerReferences"} {
+		if v, exist := objMeta[k]; exist {
+			metadata[k] = v
+		}
+	}
+	patch["metadata"] = metadata
+
+	return patch, nil
+}
+
+// Unstructured content of the object
+func objectContent(obj runtime.Object) (map[string]interface{}, error) {
+	if u, isUnstructured := obj.(runtime.Unstructured); isUnstructured {
+		return runtime.DeepCopyJSON(u.UnstructuredContent()), nil
+	}
+	return runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+}
+
+// Whether the fields set in the desired content have the same values in the current content
+// Fields not set (or set to zero values) in the desired content are not compared, as they may be defaulted by the server
+// Lists should have the same length, as they are replaced by the patch
+func fieldsMatch(desired, current interface{}) bool {
+	switch d := desired.(type) {
+	case nil:
+		return true
+	case map[string]interface{}:
+		cur, _ := current.(map[string]interface{})
+		for k, v := range d {
+			if !fieldsMatch(v, cur[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		cur, _ := current.([]interface{})
+		if len(d) != len(cur) {
+			return false
+		}
+		for i := range d {
+			if !fieldsMatch(d[i], cur[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		if current == nil {
+			return reflect.ValueOf(desired).IsZero()
+		}
+		// Numbers may be decoded in different types (e.g., int and int64)
+		if dn, isNum := toFloat(desired); isNum {
+			cn, isNum := toFloat(current)
+			return isNum && dn == cn
+		}
+		return reflect.DeepEqual(desired, current)
+	}
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// Protocol of the service port, defaulted to TCP as the server does
+func serviceProtocol(protocol corev1.Protocol) corev1.Protocol {
+	if protocol == "" {
+		return corev1.ProtocolTCP
+	}
+	return protocol
+}
+
+func objectHash(patch map[string]interface{}) (string, error) {
+	// Hash of the object excluding the hash annotation itself
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
@@ -0,0 +1,10 @@
+package apis
+
+import (
+	"github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1beta2"
+)
+
+func init() {
+	// Register the types with the Scheme so the components can map objects to GroupVersionKinds and back
+	AddToSchemes = append(AddToSchemes, v1beta2.SchemeBuilder.AddToScheme)
+}
@@ -5,6 +5,21 @@ import (
 )
 
 const (
+	DbTypeOracle             = "oracle"
 	DbTypeTibero             = "tibero"
 	ConditionKeyProjectReady = status.ConditionType("Ready")
+
+	// Finalizer of TupWAS/TupDB, to delete the resources not owned by them (e.g., ones created by the deploy task)
+	Finalizer = "tmax.io/cleanup"
+)
+
+// Reasons of the events recorded on TupWAS/TupDB
+const (
+	EventReasonReconcileFailed      = "ReconcileFailed"
+	EventReasonHostAssigned         = "HostAssigned"
+	EventReasonPipelineRunStarted   = "PipelineRunStarted"
+	EventReasonPipelineRunSucceeded = "PipelineRunSucceeded"
+	EventReasonPipelineRunFailed    = "PipelineRunFailed"
+	EventReasonTargetReady          = "TargetReady"
+	EventReasonRequested            = "Requested"
 )
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"fmt"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	TaskStateNotStarted = "NotStarted"
+	TaskStateRunning    = "Running"
+)
+
+// Generate progress of the pipeline tasks from the PipelineRun (pr can be nil)
+func GenTaskProgress(taskNames []string, pr *tektonv1.PipelineRun) []TaskProgress {
+	var progress []TaskProgress
+	for _, name := range taskNames {
+		p := TaskProgress{Name: name, State: TaskStateNotStarted}
+		if pr != nil {
+			for trName, tr := range pr.Status.TaskRuns {
+				if tr.PipelineTaskName != name || tr.Status == nil {
+					continue
+				}
+				p.TaskRunName = trName
+				p.StartTime = tr.Status.StartTime
+				p.CompletionTime = tr.Status.CompletionTime
+				p.State = TaskStateRunning
+				if len(tr.Status.Conditions) != 0 {
+					cond := tr.Status.Conditions[0]
+					if cond.Reason != "" {
+						p.State = cond.Reason
+					}
+					if cond.Status == corev1.ConditionFalse {
+						p.Message = cond.Message
+					}
+				}
+				// Message of the failing step
+				for _, step := range tr.Status.Steps {
+					if step.Terminated != nil && step.Terminated.ExitCode != 0 {
+						p.Message = fmt.Sprintf("step %s failed (exit code %d): %s", step.Name, step.Terminated.ExitCode, step.Terminated.Reason)
+						break
+					}
+				}
+				break
+			}
+		}
+		progress = append(progress, p)
+	}
+
+	return progress
+}
@@ -0,0 +1,26 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskProgress is a progress of a pipeline task
+type TaskProgress struct {
+	// Pipeline task name
+	Name string `json:"name"`
+
+	// TaskRun name
+	TaskRunName string `json:"taskRunName,omitempty"`
+
+	// State of the TaskRun (NotStarted, Pending, Running, Succeeded, Failed, ...)
+	State string `json:"state"`
+
+	// Start time of the TaskRun
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Completion time of the TaskRun
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Message of the failing step
+	Message string `json:"message,omitempty"`
+}
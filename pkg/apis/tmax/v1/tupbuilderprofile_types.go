@@ -0,0 +1,50 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TupBuilderProfileSpec defines the desired state of TupBuilderProfile
+type TupBuilderProfileSpec struct {
+	// Target WAS type (TupWAS spec.to.type), to which this profile is applied
+	TargetType string `json:"targetType"`
+
+	// S2I builder image for the target WAS
+	BuilderImage string `json:"builderImage"`
+
+	// Container port of the target WAS
+	Port int32 `json:"port"`
+
+	// HTTP path for checking health of the target WAS
+	HealthCheckPath string `json:"healthCheckPath,omitempty"`
+
+	// Default JVM options for the target WAS
+	JvmOptions string `json:"jvmOptions,omitempty"`
+
+	// User ID to run the target WAS container
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TupBuilderProfile is the Schema for the tupbuilderprofiles API
+// +kubebuilder:resource:path=tupbuilderprofiles,scope=Cluster
+type TupBuilderProfile struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TupBuilderProfileSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TupBuilderProfileList contains a list of TupBuilderProfile
+type TupBuilderProfileList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TupBuilderProfile `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TupBuilderProfile{}, &TupBuilderProfileList{})
+}
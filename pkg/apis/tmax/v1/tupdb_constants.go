@@ -3,13 +3,25 @@ package v1
 import "github.com/operator-framework/operator-sdk/pkg/status"
 
 const (
+	DBConditionKeyDBReady     = status.ConditionType("Ready")
 	DBConditionKeyDBAnalyzing = status.ConditionType("Analyzing")
 	DBConditionKeyDBMigrating = status.ConditionType("Migrating")
 	DBConditionKeyDBSucceed   = status.ConditionType("Succeeded")
 )
 
 const (
-	TupDBSecretName = "tup-db-secret"
+	// Key of the Secrets, generated by the operator for inline passwords
+	DBPasswordSecretKey = "password"
+
+	// Inline passwords of v1 not moved to Secrets yet, kept while the TupDB is converted to v1beta2 and back
+	// Removed when the passwords are moved to Secrets by the controller
+	DBAnnotationSourcePassword = "tmax.io/source-password"
+	DBAnnotationTargetPassword = "tmax.io/target-password"
+)
+
+const (
+	// Default listener port of the source Oracle DB
+	DBDefaultOraclePort = 1521
 )
 
 const (
@@ -25,7 +37,6 @@ const (
 // Params for migrate
 const (
 	DBPipelineParamNameSourceUserName = "source-username"
-	DBPipelineParamNameSourcePassword = "source-password"
 	DBPipelineParamNameSourceType     = "source-type"
 	DBPipelineParamNameSourceSID      = "source-sid"
 	DBPipelineParamNameSourceAs       = "source-as"
@@ -37,7 +48,6 @@ const (
 	DBPipelineParamNameTargetUser     = "target-user" //[TODO] Figure out what it is
 	DBPipelineParamNameTargetSID      = "target-sid"
 	DBPipelineParamNameTargetType     = "target-type"
-	DBPipelineParamNameTargetPassword = "target-password"
 	DBPipelineParamNameFull           = "full"
 )
 
@@ -41,7 +41,7 @@ func (s *TupDBStatus) SetDefaults() {
 	s.SetDefaultConditions()
 }
 
-var tupDBConditions = []status.ConditionType{DBConditionKeyDBAnalyzing, DBConditionKeyDBMigrating, DBConditionKeyDBSucceed}
+var tupDBConditions = []status.ConditionType{DBConditionKeyDBReady, DBConditionKeyDBAnalyzing, DBConditionKeyDBMigrating, DBConditionKeyDBSucceed}
 
 func (s *TupDBStatus) SetDefaultConditions() {
 	s.Conditions = nil
@@ -61,6 +61,15 @@ func (s *TupDBStatus) SetDefaultConditions() {
 	}
 }
 
+// Add the default conditions missing in the status, e.g., Ready condition of the TupDBs created by the older versions
+func (s *TupDBStatus) SetMissingConditions() {
+	for _, t := range tupDBConditions {
+		if _, found := s.GetCondition(t); !found {
+			s.Conditions = s.SetCondition(t, corev1.ConditionFalse, "", "")
+		}
+	}
+}
+
 func (t *TupDB) GenAnalyzePipelineName() string {
 	return t.Name + "-analyze"
 }
@@ -69,6 +78,20 @@ func (t *TupDB) GenMigratePipelineName() string {
 	return t.Name + "-migrate"
 }
 
+// Secret of the source/target DB credentials, mounted to the migration task
+func (t *TupDB) GenMigrateSecretName() string {
+	return t.Name + "-migrate-secret"
+}
+
+// Secret names for the inline passwords, which are moved to Secrets by the operator
+func (t *TupDB) GenSourcePasswordSecretName() string {
+	return t.Name + "-source-password"
+}
+
+func (t *TupDB) GenTargetPasswordSecretName() string {
+	return t.Name + "-target-password"
+}
+
 func (t *TupDB) GenLabels() map[string]string {
 	return map[string]string{
 		"tupDB":     t.Name,
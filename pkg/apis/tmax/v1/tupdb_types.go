@@ -2,6 +2,7 @@ package v1
 
 import (
 	"github.com/operator-framework/operator-sdk/pkg/status"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,8 +36,12 @@ type TupDBFrom struct {
 	User string `json:"user,omitempty"`
 
 	// Current DB password
+	// Deprecated: moved to a Secret by the operator, use passwordSecretRef instead
 	Password string `json:"password,omitempty"`
 
+	// Secret key containing the current DB password
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
 	// Current DB SID
 	Sid string `json:"sid,omitempty"`
 }
@@ -53,8 +58,12 @@ type TupDBTo struct {
 	User string `json:"user,omitempty"`
 
 	// Password for target DB
+	// Deprecated: moved to a Secret by the operator, use passwordSecretRef instead
 	Password string `json:"password,omitempty"`
 
+	// Secret key containing the password for target DB
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
 	// Current DB SID
 	Sid string `json:"sid,omitempty"`
 }
@@ -66,6 +75,9 @@ type TupDBStatus struct {
 	// Add custom validation using kubebuilder tags: https://book-v1.book.kubebuilder.io/beyond_basics/generating_crd.html
 	Conditions []status.Condition `json:"conditions,omitempty"`
 
+	// Generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	LastAnalyzeResult string `json:"lastAnalyzeResult,omitempty"`
 
 	// Target DB host
@@ -74,6 +86,9 @@ type TupDBStatus struct {
 
 	// Target DB port
 	TargetPort int32 `json:"targetPort,omitempty"`
+
+	// Progress of each pipeline task, of the analyze/migrate PipelineRuns
+	Progress []TaskProgress `json:"progress,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
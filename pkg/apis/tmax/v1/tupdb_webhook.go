@@ -0,0 +1,66 @@
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Defaulting/validation of TupDB, called by the admission webhooks of the operator
+
+// Supported target DB types for each source DB type
+var tupDBTypeCombinations = map[string][]string{
+	DbTypeOracle: {DbTypeTibero},
+}
+
+// Fill in the default values of the spec
+func (t *TupDB) Default() {
+	if t.Spec.From.Type == DbTypeOracle && t.Spec.From.Port == 0 {
+		t.Spec.From.Port = DBDefaultOraclePort
+	}
+}
+
+// Validate the spec, which is not validated by the CRD schema
+func (t *TupDB) Validate() field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	targets, supported := tupDBTypeCombinations[t.Spec.From.Type]
+	if !supported {
+		errs = append(errs, field.NotSupported(specPath.Child("from", "type"), t.Spec.From.Type, []string{DbTypeOracle}))
+	} else {
+		found := false
+		for _, target := range targets {
+			if target == t.Spec.To.Type {
+				found = true
+			}
+		}
+		if !found {
+			errs = append(errs, field.NotSupported(specPath.Child("to", "type"), t.Spec.To.Type, targets))
+		}
+	}
+
+	if t.Spec.From.Host == "" {
+		errs = append(errs, field.Required(specPath.Child("from", "host"), ""))
+	}
+	if t.Spec.From.Port < 1 || t.Spec.From.Port > 65535 {
+		errs = append(errs, field.Invalid(specPath.Child("from", "port"), t.Spec.From.Port, "should be between 1 and 65535"))
+	}
+
+	// Password is given either inline or as a Secret reference
+	if t.Spec.From.Password != "" && t.Spec.From.PasswordSecretRef != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("from", "password"), "may not be set with passwordSecretRef"))
+	}
+	if t.Spec.To.Password != "" && t.Spec.To.PasswordSecretRef != nil {
+		errs = append(errs, field.Forbidden(specPath.Child("to", "password"), "may not be set with passwordSecretRef"))
+	}
+
+	// PVC of the target DB is created with the size
+	sizePath := specPath.Child("to", "storageSize")
+	if size, err := resource.ParseQuantity(t.Spec.To.StorageSize); err != nil {
+		errs = append(errs, field.Invalid(sizePath, t.Spec.To.StorageSize, err.Error()))
+	} else if size.Sign() <= 0 {
+		errs = append(errs, field.Invalid(sizePath, t.Spec.To.StorageSize, "should be a positive quantity"))
+	}
+
+	return errs
+}
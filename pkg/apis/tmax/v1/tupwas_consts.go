@@ -6,8 +6,11 @@ import (
 )
 
 const (
-	WasTypeWeblogic = "weblogic"
-	WasTypeJeus     = "jeus"
+	WasTypeWeblogic  = "weblogic"
+	WasTypeWebsphere = "websphere"
+	WasTypeJboss     = "jboss"
+	WasTypeTomcat    = "tomcat"
+	WasTypeJeus      = "jeus"
 
 	WasServiceTypeClusterIP    = string(corev1.ServiceTypeClusterIP)
 	WasServiceTypeNodePort     = string(corev1.ServiceTypeNodePort)
@@ -20,6 +23,27 @@ const (
 	WasConditionKeyProjectAnalyzing = status.ConditionType("Analyzing")
 	WasConditionKeyProjectRunning   = status.ConditionType("Running")
 	WasConditionKeyProjectSucceeded = status.ConditionType("Succeeded")
+	WasConditionKeyGatePassed       = status.ConditionType("GatePassed")
+)
+
+const (
+	WasDefaultHistoryLimit = 5
+
+	// Label of PipelineRuns, whose value is WasPipelineType*
+	WasLabelKeyPipeline = "pipeline"
+
+	WasPipelineTypeAnalyze     = "analyze"
+	WasPipelineTypeBuildDeploy = "build-deploy"
+	WasPipelineTypeDeploy      = "deploy"
+
+	// Number of images kept in status.imageHistory
+	WasImageHistoryLimit = 10
+)
+
+const (
+	WasAutoRunNever            = "never"
+	WasAutoRunOnAnalyzeSuccess = "onAnalyzeSuccess"
+	WasAutoRunOnGatePass       = "onGatePass"
 )
 
 // TaskName* : Actual name of Task object
@@ -43,16 +67,98 @@ const (
 )
 
 const (
-	WasPipelineParamNameProjectId  = "project-id"
-	WasPipelineParamNameGitUrl     = "git-url"
-	WasPipelineParamNameGitRev     = "git-rev"
-	WasPipelineParamNameSourceType = "source-type"
-	WasPipelineParamNameTargetType = "target-type"
+	WasPipelineParamNameProjectId     = "project-id"
+	WasPipelineParamNameGitUrl        = "git-url"
+	WasPipelineParamNameGitRev        = "git-rev"
+	WasPipelineParamNameSourceType    = "source-type"
+	WasPipelineParamNameTargetType    = "target-type"
+	WasPipelineParamNameIgnorePattern = "ignore-pattern"
 
 	WasPipelineParamNameAppName   = "app-name"
 	WasPipelineParamNameDeployCfg = "deploy-cfg-name"
+	WasPipelineParamNameDeployEnv = "deploy-env-json"
+	WasPipelineParamNameImageUrl  = "image-url"
+)
+
+// TaskResultName* : Result name of Task
+const (
+	WasTaskResultNameSummary     = "summary"
+	WasTaskResultNameCommit      = "commit"
+	WasTaskResultNameImageUrl    = "image-url"
+	WasTaskResultNameImageDigest = "image-digest"
+)
+
+const (
+	WasPipelineWorkspaceName      = "git-report"
+	WasPipelineWorkspaceNameSslCa = "git-ssl-ca"
 )
 
 const (
-	WasPipelineWorkspaceName = "git-report"
+	WasGitDefaultRevision = "master"
+
+	WasGitSecretKeyCa       = "ca.crt"
+	WasGitSecretAnnotation0 = "tekton.dev/git-0"
+)
+
+const (
+	WasWebhookSecretKey = "secret"
+
+	// Marks the analyze PipelineRun, after which build/deploy should be executed (value is "true")
+	// Set when the analysis is created, by git webhook or spec.autoRun, and removed once build/deploy is launched or skipped
+	WasAnnotationBuildDeployAfter = "tmax.io/build-deploy-after"
+
+	// Value is the creation time of the PipelineRun in Unix nanoseconds, to order runs created in the same second
+	WasAnnotationSequence = "tmax.io/sequence"
+)
+
+const (
+	WasEnvNameJvmOptions = "JAVA_OPTS"
+)
+
+const (
+	// Issued certificates are valid for a year, and reissued a month before they expire
+	WasCertValidDays   = 365
+	WasCertRenewalDays = 30
+)
+
+const (
+	WasDefaultHealthCheckPath           = "/"
+	WasDefaultHealthInitialDelaySeconds = 60
+	WasDefaultHealthFailureThreshold    = 3
+
+	// Liveness probe waits longer than the readiness probe, not to restart the WAS being slow to start or busy
+	WasLivenessExtraDelaySeconds    = 60
+	WasLivenessFailureThresholdRate = 2
+)
+
+const (
+	WasDefaultAutoscalingMinReplicas         = 1
+	WasDefaultAutoscalingTargetCPUPercentage = 80
+)
+
+const (
+	WasRolloutStrategyRecreate  = "recreate"
+	WasRolloutStrategyBlueGreen = "blueGreen"
+	WasRolloutStrategyCanary    = "canary"
+
+	// Label of WAS pods, whose value is WasTrack*
+	WasLabelKeyTrack = "track"
+
+	// Track label of the pods of the stable deployment (named after the TupWAS), which is not in its selector
+	WasTrackStable = "stable"
+
+	// Tracks of WAS deployments, other than the stable one
+	WasTrackCanary = "canary"
+	WasTrackBlue   = "blue"
+	WasTrackGreen  = "green"
+
+	WasRolloutPhaseProgressing = "Progressing"
+	WasRolloutPhasePromoted    = "Promoted"
+	WasRolloutPhaseRolledBack  = "RolledBack"
+
+	WasDefaultCanaryReplicas        = 1
+	WasDefaultCanaryDurationSeconds = 300
+
+	// Default progressDeadlineSeconds of deployments, after which the new image is considered failed
+	WasDefaultProgressDeadlineSeconds = 600
 )
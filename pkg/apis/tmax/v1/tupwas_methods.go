@@ -1,11 +1,16 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 	"github.com/operator-framework/operator-sdk/pkg/status"
 	"github.com/tmax-cloud/l2c-operator/internal"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"strconv"
+	"strings"
+	"time"
 )
 
 func (s *TupWASStatus) GetCondition(key status.ConditionType) (*status.Condition, bool) {
@@ -73,23 +78,213 @@ func (t *TupWAS) GenLabels() map[string]string {
 	}
 }
 
-func (t *TupWAS) GenBuilderImage() (string, error) {
-	switch t.Spec.To.Type {
-	case "jeus:7":
-		return internal.BuilderImageJeus7, nil
-	case "jeus:8":
-		return internal.BuilderImageJeus8, nil
-	default:
-		return "", fmt.Errorf("%s was type is not supported", t.Spec.To.Type)
+// WasAnalyzeParam is a set of analyzer parameters for a source WAS type
+type WasAnalyzeParam struct {
+	// Source technology passed to the analyzer (empty if analyzer has no rule set for the source)
+	Source string
+
+	// Files that should not be analyzed
+	IgnorePattern string
+}
+
+var wasAnalyzeParams = map[string]WasAnalyzeParam{
+	WasTypeWeblogic: {
+		Source:        "weblogic",
+		IgnorePattern: `\.class$`,
+	},
+	WasTypeWebsphere: {
+		Source:        "websphere",
+		IgnorePattern: `\.class$`,
+	},
+	WasTypeJboss: {
+		Source:        "eap",
+		IgnorePattern: `\.class$|/standalone/(data|log|tmp)/`,
+	},
+	WasTypeTomcat: {
+		// Analyzer does not have a Tomcat rule set, analyze against the target rules only
+		Source:        "",
+		IgnorePattern: `\.class$|/work/Catalina/`,
+	},
+}
+
+func (t *TupWAS) GenAnalyzeParam() (*WasAnalyzeParam, error) {
+	param, ok := wasAnalyzeParams[t.Spec.From.Type]
+	if !ok {
+		return nil, fmt.Errorf("spec.from.type(%s) not supported", t.Spec.From.Type)
 	}
+	return &param, nil
+}
+
+// Evaluate the quality gate against the last analysis summary
+// Returns whether the gate is passed and the reason why it is not passed
+// Even without the gate, the source should be analyzed and not changed after the analysis
+func (t *TupWAS) EvaluateQualityGate() (bool, string) {
+	if t.Status.ReportStale {
+		return false, "analysis report is stale, the source is changed after it is analyzed"
+	}
+	summary := t.Status.AnalyzeSummary
+	if summary == nil {
+		return false, "analysis summary is not available"
+	}
+
+	gate := t.Spec.QualityGate
+	if gate == nil {
+		return true, ""
+	}
+
+	var violations []string
+	if gate.MaxMandatoryIssues != nil && summary.MandatoryIssues > *gate.MaxMandatoryIssues {
+		violations = append(violations, fmt.Sprintf("mandatory issues %d exceed %d", summary.MandatoryIssues, *gate.MaxMandatoryIssues))
+	}
+	if gate.MaxStoryPoints != nil && summary.StoryPoints > *gate.MaxStoryPoints {
+		violations = append(violations, fmt.Sprintf("story points %d exceed %d", summary.StoryPoints, *gate.MaxStoryPoints))
+	}
+	if len(violations) != 0 {
+		return false, strings.Join(violations, ", ")
+	}
+
+	return true, ""
 }
 
 func (t *TupWAS) GenAnalyzePipelineName() string {
-	return t.GenResourceName() + "-analyze"
+	return t.GenResourceName() + "-" + WasPipelineTypeAnalyze
 }
 
 func (t *TupWAS) GenBuildDeployPipelineName() string {
-	return t.GenResourceName() + "-build-deploy"
+	return t.GenResourceName() + "-" + WasPipelineTypeBuildDeploy
+}
+
+func (t *TupWAS) GenDeployPipelineName() string {
+	return t.GenResourceName() + "-" + WasPipelineTypeDeploy
+}
+
+// Labels for PipelineRuns, to find runs of the pipeline type (WasPipelineType*)
+func (t *TupWAS) GenPipelineRunLabels(pipelineType string) map[string]string {
+	labels := t.GenLabels()
+	labels[WasLabelKeyPipeline] = pipelineType
+	return labels
+}
+
+// Annotations for PipelineRuns, to order runs whose creation timestamps are the same
+func (t *TupWAS) GenPipelineRunAnnotations() map[string]string {
+	return map[string]string{
+		WasAnnotationSequence: strconv.FormatInt(time.Now().UnixNano(), 10),
+	}
+}
+
+func (t *TupWAS) GenHistoryLimit() int {
+	if t.Spec.HistoryLimit == nil {
+		return WasDefaultHistoryLimit
+	}
+	return int(*t.Spec.HistoryLimit)
+}
+
+// Environment variables with plain values (and JVM options) of the WAS in JSON object form, for deploy-env-json param
+// Ones from ConfigMaps/Secrets are set in the deployment spec
+func (t *TupWAS) GenDeployEnvJson() string {
+	env := map[string]string{}
+	for _, e := range t.Spec.To.Env {
+		if e.ValueFrom == nil {
+			env[e.Name] = e.Value
+		}
+	}
+	if t.Spec.To.JvmOptions != "" {
+		env[WasEnvNameJvmOptions] = t.Spec.To.JvmOptions
+	}
+
+	// Marshaling a string map never fails
+	envJson, _ := json.Marshal(env)
+	return string(envJson)
+}
+
+// Readiness probe of the WAS container, filled with the defaults of the profile
+func (t *TupWAS) GenHealthProbe(profile *TupBuilderProfile) *corev1.Probe {
+	path := profile.Spec.HealthCheckPath
+	if path == "" {
+		path = WasDefaultHealthCheckPath
+	}
+	port := profile.Spec.Port
+	initialDelay := int32(WasDefaultHealthInitialDelaySeconds)
+	failureThreshold := int32(WasDefaultHealthFailureThreshold)
+
+	if health := t.Spec.To.Health; health != nil {
+		if health.Path != "" {
+			path = health.Path
+		}
+		if health.Port != nil {
+			port = *health.Port
+		}
+		if health.InitialDelaySeconds != nil {
+			initialDelay = *health.InitialDelaySeconds
+		}
+		if health.FailureThreshold != nil {
+			failureThreshold = *health.FailureThreshold
+		}
+	}
+
+	return &corev1.Probe{
+		Handler: corev1.Handler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: path,
+				Port: intstr.FromInt(int(port)),
+			},
+		},
+		InitialDelaySeconds: initialDelay,
+		FailureThreshold:    failureThreshold,
+	}
+}
+
+// Liveness probe of the WAS container, checking the same endpoint
+// Initial delay and failure threshold are longer than the readiness probe, unless they are given explicitly
+func (t *TupWAS) GenLivenessProbe(profile *TupBuilderProfile) *corev1.Probe {
+	probe := t.GenHealthProbe(profile)
+	health := t.Spec.To.Health
+	if health == nil || health.InitialDelaySeconds == nil {
+		probe.InitialDelaySeconds += WasLivenessExtraDelaySeconds
+	}
+	if health == nil || health.FailureThreshold == nil {
+		probe.FailureThreshold *= WasLivenessFailureThresholdRate
+	}
+	return probe
+}
+
+func (t *TupWAS) GenAutoRun() string {
+	if t.Spec.AutoRun == "" {
+		return WasAutoRunNever
+	}
+	return t.Spec.AutoRun
+}
+
+// Whether build/deploy should be launched after an analysis, which is marked by the build-deploy-after annotation
+// - by spec.autoRun, for all analyses
+// - by spec.from.webhook.buildDeploy, for the analyses triggered by git push events
+func (t *TupWAS) IsBuildDeployAfterAnalysis(byWebhook bool) bool {
+	if t.GenAutoRun() != WasAutoRunNever {
+		return true
+	}
+	return byWebhook && t.Spec.From.Webhook != nil && t.Spec.From.Webhook.BuildDeploy
+}
+
+// Git revision to be analyzed/built - defaults to master, as git-clone task does
+func (t *TupWAS) GenGitRevision() string {
+	if t.Spec.From.Git.Revision == "" {
+		return WasGitDefaultRevision
+	}
+	return t.Spec.From.Git.Revision
+}
+
+// Whether the git source of the spec is different from the source (e.g., the one analyzed)
+func (t *TupWAS) IsSourceChanged(source *AnalyzedSource) bool {
+	// Empty revision is the default one, which may be filled in by the webhook later
+	revision := source.Revision
+	if revision == "" {
+		revision = WasGitDefaultRevision
+	}
+	return source.Url != t.Spec.From.Git.Url || revision != t.GenGitRevision()
+}
+
+func (t *TupWAS) GenGitSecretName() string {
+	return t.GenResourceName() + "-git"
 }
 
 // Supporting functions for WAS resources
@@ -112,13 +307,78 @@ func (t *TupWAS) GenWasServiceLabels() map[string]string {
 	}
 }
 
-func (t *TupWAS) GenWasPort() (int32, error) {
-	switch t.Spec.To.Type {
-	case "jeus:7", "jeus:8":
-		return 8080, nil
-	default:
-		return 0, fmt.Errorf("spec.was.to.type(%s) not supported", t.Spec.To.Type)
+// Tracks of the WAS deployments, for the rollout strategy
+func (t *TupWAS) GenRolloutTracks() []string {
+	switch t.Spec.To.RolloutStrategy {
+	case WasRolloutStrategyBlueGreen:
+		return []string{"", WasTrackBlue, WasTrackGreen}
+	case WasRolloutStrategyCanary:
+		return []string{"", WasTrackCanary}
 	}
+	return []string{""}
+}
+
+// Track of the deployment, to which the next build is deployed
+func (t *TupWAS) GenRolloutTrack() string {
+	switch t.Spec.To.RolloutStrategy {
+	case WasRolloutStrategyBlueGreen:
+		if t.Status.Rollout != nil && t.Status.Rollout.ActiveTrack == WasTrackBlue {
+			return WasTrackGreen
+		}
+		return WasTrackBlue
+	case WasRolloutStrategyCanary:
+		// The first image is deployed to the stable deployment, as there is nothing to compare with
+		if t.Status.Rollout != nil && t.Status.Rollout.StableImage != "" {
+			return WasTrackCanary
+		}
+	}
+	return ""
+}
+
+// Name of the WAS deployment of the track, the stable one is named after the TupWAS
+func (t *TupWAS) GenWasDeployName(track string) string {
+	if track == "" {
+		return t.Name
+	}
+	return fmt.Sprintf("%s-%s", t.Name, track)
+}
+
+// Name of the ConfigMap having the WAS deployment spec of the track
+func (t *TupWAS) GenWasDeployConfigName(track string) string {
+	if track == "" {
+		return t.GenWasResourceName()
+	}
+	return fmt.Sprintf("%s-%s", t.GenWasResourceName(), track)
+}
+
+// Name of the WAS deployment the service routes to (other than canary)
+func (t *TupWAS) GenWasActiveDeployName() string {
+	if t.Status.Rollout == nil {
+		return t.GenWasDeployName("")
+	}
+	return t.GenWasDeployName(t.Status.Rollout.ActiveTrack)
+}
+
+// Selector of the WAS service, which only selects the active track for blueGreen strategy
+// The stable track is selected until the first promotion, not to route to the blue/green pods being rolled out
+func (t *TupWAS) GenWasServiceSelector() map[string]string {
+	selector := t.GenWasServiceLabels()
+	if t.Status.Rollout != nil && t.Status.Rollout.ActiveTrack != "" {
+		selector[WasLabelKeyTrack] = t.Status.Rollout.ActiveTrack
+	} else if t.Spec.To.RolloutStrategy == WasRolloutStrategyBlueGreen {
+		selector[WasLabelKeyTrack] = WasTrackStable
+	}
+	return selector
+}
+
+func (t *TupWAS) GenIngressHostTemplate() string {
+	if t.Spec.IngressHostTemplate != "" {
+		return t.Spec.IngressHostTemplate
+	}
+	if internal.IngressHostTemplate != "" {
+		return internal.IngressHostTemplate
+	}
+	return internal.DefaultIngressHostTemplate
 }
 
 func (t *TupWAS) GenIngressAnnotation() map[string]string {
@@ -126,3 +386,26 @@ func (t *TupWAS) GenIngressAnnotation() map[string]string {
 		"kubernetes.io/ingress.class": internal.IngressClass,
 	}
 }
+
+// Reference of the image to be deployed - pinned by the digest (<repository>@<digest>), if it is recorded
+// Tags are mutable, so the tag may point to another image when it is rolled back
+func (h *ImageHistory) GenImageReference() string {
+	if h.Digest == "" {
+		return h.Image
+	}
+
+	repository := h.Image
+	if i := strings.Index(repository, "@"); i >= 0 {
+		repository = repository[:i]
+	}
+	// Colon after the last slash is of the tag, not of the registry port
+	if i := strings.LastIndex(repository, ":"); i > strings.LastIndex(repository, "/") {
+		repository = repository[:i]
+	}
+
+	digest := h.Digest
+	if !strings.Contains(digest, ":") {
+		digest = "sha256:" + digest
+	}
+	return fmt.Sprintf("%s@%s", repository, digest)
+}
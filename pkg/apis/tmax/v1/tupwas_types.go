@@ -2,6 +2,7 @@ package v1
 
 import (
 	"github.com/operator-framework/operator-sdk/pkg/status"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,6 +13,27 @@ type TupWASSpec struct {
 
 	// WAS destination configuration
 	To TupWasTo `json:"to"`
+
+	// Quality gate, which should be passed before build/deploy
+	QualityGate *TupWasQualityGate `json:"qualityGate,omitempty"`
+
+	// Policy to launch build/deploy automatically after an analysis
+	// Default value is never
+	// - never: build/deploy is launched only by the run API
+	// - onAnalyzeSuccess: build/deploy is launched after each successful analysis, regardless of the quality gate
+	// - onGatePass: build/deploy is launched after each successful analysis, only if the quality gate is passed
+	// +kubebuilder:validation:Enum=never;onAnalyzeSuccess;onGatePass
+	AutoRun string `json:"autoRun,omitempty"`
+
+	// Go template of the hosts of WAS/IDE ingresses, overriding the one of the operator
+	// e.g., {{.Prefix}}-{{.Name}}.{{.Namespace}}.apps.example.com
+	// Prefix is one of was, ide, report and config. Address is the IP or hostname of the load balancer
+	IngressHostTemplate string `json:"ingressHostTemplate,omitempty"`
+
+	// Number of PipelineRuns to be kept, for each of analyze and build/deploy
+	// Default value is 5
+	// +kubebuilder:validation:Minimum=1
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
 }
 
 type TupWasGit struct {
@@ -20,6 +42,19 @@ type TupWasGit struct {
 
 	// Revision to be used as a source
 	Revision string `json:"revision,omitempty"`
+
+	// Secret that contains a credential to access the git repository
+	// Secret type should be kubernetes.io/basic-auth (username/password) or kubernetes.io/ssh-auth (ssh-privatekey, known_hosts)
+	// If the secret has ca.crt key, it is used as a CA bundle to verify the git server
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
+}
+
+type TupWasWebhook struct {
+	// Secret that contains a webhook secret token, with key 'secret'
+	SecretRef corev1.LocalObjectReference `json:"secretRef"`
+
+	// If true, build/deploy is executed after the analysis triggered by a push event succeeds
+	BuildDeploy bool `json:"buildDeploy,omitempty"`
 }
 
 type TupWasImage struct {
@@ -32,19 +67,22 @@ type TupWasImage struct {
 
 type TupWasFrom struct {
 	// Current WAS type
-	// +kubebuilder:validation:Enum=weblogic
+	// +kubebuilder:validation:Enum=weblogic;websphere;jboss;tomcat
 	Type string `json:"type"`
 
 	// Git information for WAS source code
 	Git TupWasGit `json:"git"`
 
+	// Webhook configuration, to analyze the source when it is pushed to the git repository
+	Webhook *TupWasWebhook `json:"webhook,omitempty"`
+
 	// Package server URL that would be used while building the application
 	PackageServer string `json:"packageServerUrl,omitempty"`
 }
 
 type TupWasTo struct {
 	// Target WAS type, to be migrated
-	// +kubebuilder:validation:Enum="jeus:7";"jeus:8"
+	// There should be a TupBuilderProfile whose targetType is this type
 	Type string `json:"type"`
 
 	// Image, in which the built application image would be saved
@@ -54,10 +92,154 @@ type TupWasTo struct {
 	// Default value is Ingress
 	// +kubebuilder:validation:Enum=Ingress;ClusterIP;NodePort;LoadBalancer
 	ServiceType string `json:"serviceType,omitempty"`
+
+	// Secret (kubernetes.io/tls type) for the ingresses of the TupWAS (WAS and IDE/report/config), which should cover all of their hosts
+	// If it is not set, a certificate is issued by the CA of the operator, if configured
+	TLSSecret string `json:"tlsSecret,omitempty"`
+
+	// Number of WAS pods
+	// Default value is 1
+	// +kubebuilder:validation:Minimum=0
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// CPU/memory requests and limits of the WAS container
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Environment variables of the WAS container
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// ConfigMaps/Secrets to populate environment variables of the WAS container
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Volumes (ConfigMap, Secret, PVC or emptyDir) mounted to the WAS container
+	Volumes []TupWasVolume `json:"volumes,omitempty"`
+
+	// Node selector of the WAS pods
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Tolerations of the WAS pods
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
+
+	// JVM options of the WAS, which override the default options of the TupBuilderProfile
+	JvmOptions string `json:"jvmOptions,omitempty"`
+
+	// Health check (readiness/liveness probes) of the WAS container
+	Health *TupWasHealth `json:"health,omitempty"`
+
+	// Horizontal pod autoscaling of the WAS
+	// If it is set, replicas is managed by the autoscaler
+	Autoscaling *TupWasAutoscaling `json:"autoscaling,omitempty"`
+
+	// Strategy to roll out the rebuilt WAS image
+	// If it is not set, the running deployment is updated in place (rolling update)
+	// recreate: the running pods are stopped before the new pods start
+	// blueGreen: the new image is deployed to another deployment, and the service is switched to it after it gets ready
+	// canary: canary pods of the new image share the traffic with the stable pods, and the new image is promoted after they stay ready
+	// If the new image fails the health check, the WAS is rolled back to status.rollout.stableImage
+	// +kubebuilder:validation:Enum=recreate;blueGreen;canary
+	RolloutStrategy string `json:"rolloutStrategy,omitempty"`
+
+	// Canary rollout configuration, used if rolloutStrategy is canary
+	Canary *TupWasCanary `json:"canary,omitempty"`
+}
+
+type TupWasCanary struct {
+	// Number of canary pods, traffic is split by the ratio of canary pods to the stable pods
+	// Default value is 1
+	// +kubebuilder:validation:Minimum=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Seconds the canary pods should stay ready, before the new image is promoted
+	// Default value is 300
+	// +kubebuilder:validation:Minimum=0
+	DurationSeconds *int32 `json:"durationSeconds,omitempty"`
+}
+
+type TupWasAutoscaling struct {
+	// Lower limit of the number of WAS pods
+	// Default value is 1
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Upper limit of the number of WAS pods
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+
+	// Target average CPU utilization, in percentage of the requested CPU
+	// Default value is 80
+	// +kubebuilder:validation:Minimum=1
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+
+	// Target average memory utilization, in percentage of the requested memory
+	// Memory is not considered if it is not set
+	// +kubebuilder:validation:Minimum=1
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+}
+
+type TupWasHealth struct {
+	// HTTP path to check health of the WAS
+	// Default value is healthCheckPath of the TupBuilderProfile, or / if it is not set
+	Path string `json:"path,omitempty"`
+
+	// Port to check health of the WAS
+	// Default value is port of the TupBuilderProfile
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port *int32 `json:"port,omitempty"`
+
+	// Seconds after the container has started, before the health is checked
+	// Default value is 60, as JEUS takes a while to deploy the application
+	// +kubebuilder:validation:Minimum=0
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// Number of consecutive failures, for the WAS to be considered unhealthy
+	// Default value is 3
+	// +kubebuilder:validation:Minimum=1
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+type TupWasVolume struct {
+	// Volume name, which should be unique in the WAS pod
+	Name string `json:"name"`
+
+	// Path in the WAS container, at which the volume is mounted
+	MountPath string `json:"mountPath"`
+
+	// Path within the volume to be mounted, instead of the root of the volume
+	SubPath string `json:"subPath,omitempty"`
+
+	// If true, the volume is mounted read-only
+	ReadOnly bool `json:"readOnly,omitempty"`
+
+	// ConfigMap to be mounted
+	// Only one of configMap, secret, persistentVolumeClaim and emptyDir should be set
+	ConfigMap *corev1.ConfigMapVolumeSource `json:"configMap,omitempty"`
+
+	// Secret to be mounted
+	Secret *corev1.SecretVolumeSource `json:"secret,omitempty"`
+
+	// PersistentVolumeClaim to be mounted
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+
+	// Empty directory, which shares the lifetime of the pod
+	EmptyDir *corev1.EmptyDirVolumeSource `json:"emptyDir,omitempty"`
+}
+
+type TupWasQualityGate struct {
+	// Maximum number of mandatory issues allowed
+	// +kubebuilder:validation:Minimum=0
+	MaxMandatoryIssues *int32 `json:"maxMandatoryIssues,omitempty"`
+
+	// Maximum story points allowed
+	// +kubebuilder:validation:Minimum=0
+	MaxStoryPoints *int32 `json:"maxStoryPoints,omitempty"`
 }
 
 // TupWASStatus defines the observed state of TupWAS
 type TupWASStatus struct {
+	// Generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Start time of last analysis
 	LastAnalyzeStartTime *metav1.Time `json:"lastAnalyzeStartTime,omitempty"`
 
@@ -67,6 +249,15 @@ type TupWASStatus struct {
 	// Result of last analysis
 	LastAnalyzeResult string `json:"lastAnalyzeResult,omitempty"`
 
+	// Summary of last analysis report
+	AnalyzeSummary *AnalyzeSummary `json:"analyzeSummary,omitempty"`
+
+	// Source of the last completed analysis, which the report is about
+	AnalyzedSource *AnalyzedSource `json:"analyzedSource,omitempty"`
+
+	// Whether the analysis report is stale, as the source is changed after it is analyzed
+	ReportStale bool `json:"reportStale,omitempty"`
+
 	// Start time of last build
 	LastBuildStartTime *metav1.Time `json:"lastBuildStartTime,omitempty"`
 
@@ -82,6 +273,18 @@ type TupWASStatus struct {
 	// PipelineRun name for Build/Deploy
 	BuildPipelineRunName string `json:"buildPipelineRunName,omitempty"`
 
+	// Analyze PipelineRuns, the latest first
+	AnalyzeHistory []PipelineRunHistory `json:"analyzeHistory,omitempty"`
+
+	// Build/Deploy PipelineRuns, the latest first
+	BuildHistory []PipelineRunHistory `json:"buildHistory,omitempty"`
+
+	// Images built by Build/Deploy PipelineRuns, the latest first
+	ImageHistory []ImageHistory `json:"imageHistory,omitempty"`
+
+	// Progress of each pipeline task, of the latest PipelineRuns
+	Progress []TaskProgress `json:"progress,omitempty"`
+
 	// TupWAS project conditions
 	Conditions []status.Condition `json:"conditions,omitempty"`
 
@@ -93,6 +296,40 @@ type TupWASStatus struct {
 
 	// Migrated Was URL
 	WasUrl string `json:"wasUrl,omitempty"`
+
+	// Current number of WAS pods, observed by the autoscaler
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// Desired number of WAS pods, calculated by the autoscaler
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// Rollout of the WAS image
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+type RolloutStatus struct {
+	// Image which passed the health check, the WAS is rolled back to it if a new image fails
+	StableImage string `json:"stableImage,omitempty"`
+
+	// Track (blue or green) of the deployment the service routes to, for blueGreen strategy
+	ActiveTrack string `json:"activeTrack,omitempty"`
+
+	// Phase of the latest rollout (Progressing, Promoted or RolledBack)
+	Phase string `json:"phase,omitempty"`
+
+	// Message of the latest rollout
+	Message string `json:"message,omitempty"`
+}
+
+type AnalyzedSource struct {
+	// Git URL of the source
+	Url string `json:"url"`
+
+	// Git revision (branch, tag or commit) given in the spec
+	Revision string `json:"revision,omitempty"`
+
+	// Git commit cloned for the analysis
+	Commit string `json:"commit,omitempty"`
 }
 
 type EditorStatus struct {
@@ -100,7 +337,70 @@ type EditorStatus struct {
 	Url string `json:"url,omitempty"`
 
 	// VSCode access code
+	// Deprecated: not filled anymore, read it from the passwordSecret instead
 	Password string `json:"password,omitempty"`
+
+	// Name of the Secret containing the VSCode access code, with the key 'password'
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+}
+
+type PipelineRunHistory struct {
+	// PipelineRun name
+	Name string `json:"name"`
+
+	// Start time of the PipelineRun
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// Completion time of the PipelineRun
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+
+	// Result of the PipelineRun
+	Result string `json:"result,omitempty"`
+}
+
+type ImageHistory struct {
+	// Image URL (with the updated tag)
+	Image string `json:"image"`
+
+	// Digest of the image
+	Digest string `json:"digest,omitempty"`
+
+	// Git commit of the source, from which the image is built
+	Revision string `json:"revision,omitempty"`
+
+	// Time the image is built
+	Time *metav1.Time `json:"time,omitempty"`
+
+	// Build/Deploy PipelineRun name
+	PipelineRun string `json:"pipelineRun"`
+}
+
+type AnalyzeSummary struct {
+	// Number of mandatory issues, which should be fixed to migrate
+	MandatoryIssues int32 `json:"mandatoryIssues"`
+
+	// Number of optional issues
+	OptionalIssues int32 `json:"optionalIssues"`
+
+	// Number of potential issues, which should be reviewed
+	PotentialIssues int32 `json:"potentialIssues"`
+
+	// Total story points (estimated level of effort) of the issues
+	StoryPoints int32 `json:"storyPoints"`
+
+	// Files having the most story points
+	TopFiles []AnalyzeFileSummary `json:"topFiles,omitempty"`
+}
+
+type AnalyzeFileSummary struct {
+	// File path, relative to the source root
+	File string `json:"file"`
+
+	// Number of issues in the file
+	Issues int32 `json:"issues"`
+
+	// Story points of the issues in the file
+	StoryPoints int32 `json:"storyPoints"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -108,6 +408,13 @@ type EditorStatus struct {
 // TupWAS is the Schema for the tupwas API
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:path=tupwas,scope=Namespaced
+// +kubebuilder:printcolumn:name="Analyze",type="string",JSONPath=".status.lastAnalyzeResult",description="Result of last analysis"
+// +kubebuilder:printcolumn:name="Mandatory",type="integer",JSONPath=".status.analyzeSummary.mandatoryIssues",description="Number of mandatory issues"
+// +kubebuilder:printcolumn:name="Optional",type="integer",JSONPath=".status.analyzeSummary.optionalIssues",description="Number of optional issues"
+// +kubebuilder:printcolumn:name="Potential",type="integer",JSONPath=".status.analyzeSummary.potentialIssues",description="Number of potential issues"
+// +kubebuilder:printcolumn:name="StoryPoints",type="integer",JSONPath=".status.analyzeSummary.storyPoints",description="Total story points"
+// +kubebuilder:printcolumn:name="Gate",type="string",JSONPath=".status.conditions[?(@.type==\"GatePassed\")].status",description="Whether the quality gate is passed"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type TupWAS struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"net/url"
+	"regexp"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// Defaulting/validation of TupWAS, called by the admission webhooks of the operator
+
+var (
+	// scp-like git URL, e.g., git@github.com:tmax-cloud/l2c-operator.git
+	gitScpUrlRegexp = regexp.MustCompile(`^[A-Za-z0-9._-]+@[A-Za-z0-9.-]+:[A-Za-z0-9._/~-]+$`)
+
+	// Image reference, following the grammar of docker/distribution (domain/path:tag@digest)
+	imageReferenceRegexp = regexp.MustCompile(`^` +
+		`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*)*` +
+		`(?::[\w][\w.-]{0,127})?` +
+		`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9A-Fa-f]{32,})?$`)
+
+	gitUrlSchemes = map[string]bool{"http": true, "https": true, "ssh": true, "git": true}
+)
+
+// Fill in the default values of the spec
+func (t *TupWAS) Default() {
+	if t.Spec.From.Git.Revision == "" {
+		t.Spec.From.Git.Revision = WasGitDefaultRevision
+	}
+	if t.Spec.To.ServiceType == "" {
+		t.Spec.To.ServiceType = WasServiceTypeIngress
+	}
+	if t.Spec.AutoRun == "" {
+		t.Spec.AutoRun = WasAutoRunNever
+	}
+}
+
+// Validate the spec, which is not validated by the CRD schema
+// Existence of the TupBuilderProfile for the target type is checked by the webhook, as it needs a client
+func (t *TupWAS) Validate() field.ErrorList {
+	var errs field.ErrorList
+	specPath := field.NewPath("spec")
+
+	// Source
+	fromPath := specPath.Child("from")
+	if _, err := t.GenAnalyzeParam(); err != nil {
+		errs = append(errs, field.NotSupported(fromPath.Child("type"), t.Spec.From.Type, []string{WasTypeWeblogic, WasTypeWebsphere, WasTypeJboss, WasTypeTomcat}))
+	}
+	if err := validateGitUrl(t.Spec.From.Git.Url); err != "" {
+		errs = append(errs, field.Invalid(fromPath.Child("git", "url"), t.Spec.From.Git.Url, err))
+	}
+	if t.Spec.From.PackageServer != "" {
+		if u, err := url.Parse(t.Spec.From.PackageServer); err != nil || u.Scheme == "" || u.Host == "" {
+			errs = append(errs, field.Invalid(fromPath.Child("packageServerUrl"), t.Spec.From.PackageServer, "should be an absolute URL"))
+		}
+	}
+
+	// Target
+	toPath := specPath.Child("to")
+	if t.Spec.To.Type == "" {
+		errs = append(errs, field.Required(toPath.Child("type"), ""))
+	}
+	if !imageReferenceRegexp.MatchString(t.Spec.To.Image.Url) {
+		errs = append(errs, field.Invalid(toPath.Child("image", "url"), t.Spec.To.Image.Url, "should be an image reference, e.g., registry.example.com/app:tag"))
+	}
+	if t.Spec.To.Autoscaling != nil {
+		if t.Spec.To.Replicas != nil {
+			errs = append(errs, field.Forbidden(toPath.Child("replicas"), "replicas is managed by the autoscaler, if autoscaling is set"))
+		}
+		if min := t.Spec.To.Autoscaling.MinReplicas; min != nil && *min > t.Spec.To.Autoscaling.MaxReplicas {
+			errs = append(errs, field.Invalid(toPath.Child("autoscaling", "minReplicas"), *min, "should not be greater than maxReplicas"))
+		}
+	}
+	if t.Spec.To.Canary != nil && t.Spec.To.RolloutStrategy != WasRolloutStrategyCanary {
+		errs = append(errs, field.Forbidden(toPath.Child("canary"), "only used if rolloutStrategy is canary"))
+	}
+	volumeNames := map[string]bool{}
+	for i, v := range t.Spec.To.Volumes {
+		volPath := toPath.Child("volumes").Index(i)
+		if volumeNames[v.Name] {
+			errs = append(errs, field.Duplicate(volPath.Child("name"), v.Name))
+		}
+		volumeNames[v.Name] = true
+
+		sources := 0
+		for _, set := range []bool{v.ConfigMap != nil, v.Secret != nil, v.PersistentVolumeClaim != nil, v.EmptyDir != nil} {
+			if set {
+				sources++
+			}
+		}
+		if sources != 1 {
+			errs = append(errs, field.Invalid(volPath, v.Name, "exactly one of configMap, secret, persistentVolumeClaim and emptyDir should be set"))
+		}
+	}
+
+	if t.Spec.IngressHostTemplate != "" {
+		if _, err := template.New("host").Parse(t.Spec.IngressHostTemplate); err != nil {
+			errs = append(errs, field.Invalid(specPath.Child("ingressHostTemplate"), t.Spec.IngressHostTemplate, err.Error()))
+		}
+	}
+
+	return errs
+}
+
+// Returns the reason why the git URL is not valid, empty string if it is valid
+func validateGitUrl(gitUrl string) string {
+	if gitScpUrlRegexp.MatchString(gitUrl) {
+		return ""
+	}
+	u, err := url.Parse(gitUrl)
+	if err != nil {
+		return err.Error()
+	}
+	if !gitUrlSchemes[u.Scheme] || u.Host == "" {
+		return "should be a http(s), ssh or git URL, or in the form of user@host:path"
+	}
+	return ""
+}
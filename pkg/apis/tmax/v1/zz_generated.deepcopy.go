@@ -6,9 +6,63 @@ package v1
 
 import (
 	status "github.com/operator-framework/operator-sdk/pkg/status"
+	corev1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzeFileSummary) DeepCopyInto(out *AnalyzeFileSummary) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzeFileSummary.
+func (in *AnalyzeFileSummary) DeepCopy() *AnalyzeFileSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzeFileSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzeSummary) DeepCopyInto(out *AnalyzeSummary) {
+	*out = *in
+	if in.TopFiles != nil {
+		in, out := &in.TopFiles, &out.TopFiles
+		*out = make([]AnalyzeFileSummary, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzeSummary.
+func (in *AnalyzeSummary) DeepCopy() *AnalyzeSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzeSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AnalyzedSource) DeepCopyInto(out *AnalyzedSource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AnalyzedSource.
+func (in *AnalyzedSource) DeepCopy() *AnalyzedSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AnalyzedSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EditorStatus) DeepCopyInto(out *EditorStatus) {
 	*out = *in
@@ -25,12 +79,177 @@ func (in *EditorStatus) DeepCopy() *EditorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageHistory) DeepCopyInto(out *ImageHistory) {
+	*out = *in
+	if in.Time != nil {
+		in, out := &in.Time, &out.Time
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageHistory.
+func (in *ImageHistory) DeepCopy() *ImageHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineRunHistory) DeepCopyInto(out *PipelineRunHistory) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineRunHistory.
+func (in *PipelineRunHistory) DeepCopy() *PipelineRunHistory {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineRunHistory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TaskProgress) DeepCopyInto(out *TaskProgress) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TaskProgress.
+func (in *TaskProgress) DeepCopy() *TaskProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(TaskProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupBuilderProfile) DeepCopyInto(out *TupBuilderProfile) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupBuilderProfile.
+func (in *TupBuilderProfile) DeepCopy() *TupBuilderProfile {
+	if in == nil {
+		return nil
+	}
+	out := new(TupBuilderProfile)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TupBuilderProfile) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupBuilderProfileList) DeepCopyInto(out *TupBuilderProfileList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]TupBuilderProfile, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupBuilderProfileList.
+func (in *TupBuilderProfileList) DeepCopy() *TupBuilderProfileList {
+	if in == nil {
+		return nil
+	}
+	out := new(TupBuilderProfileList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *TupBuilderProfileList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupBuilderProfileSpec) DeepCopyInto(out *TupBuilderProfileSpec) {
+	*out = *in
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupBuilderProfileSpec.
+func (in *TupBuilderProfileSpec) DeepCopy() *TupBuilderProfileSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TupBuilderProfileSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupDB) DeepCopyInto(out *TupDB) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -56,6 +275,11 @@ func (in *TupDB) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupDBFrom) DeepCopyInto(out *TupDBFrom) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -105,8 +329,8 @@ func (in *TupDBList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupDBSpec) DeepCopyInto(out *TupDBSpec) {
 	*out = *in
-	out.From = in.From
-	out.To = in.To
+	in.From.DeepCopyInto(&out.From)
+	in.To.DeepCopyInto(&out.To)
 	return
 }
 
@@ -130,6 +354,13 @@ func (in *TupDBStatus) DeepCopyInto(out *TupDBStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = make([]TaskProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -146,6 +377,11 @@ func (in *TupDBStatus) DeepCopy() *TupDBStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupDBTo) DeepCopyInto(out *TupDBTo) {
 	*out = *in
+	if in.PasswordSecretRef != nil {
+		in, out := &in.PasswordSecretRef, &out.PasswordSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -164,7 +400,7 @@ func (in *TupWAS) DeepCopyInto(out *TupWAS) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 	return
 }
@@ -223,8 +459,18 @@ func (in *TupWASList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupWASSpec) DeepCopyInto(out *TupWASSpec) {
 	*out = *in
-	out.From = in.From
-	out.To = in.To
+	in.From.DeepCopyInto(&out.From)
+	in.To.DeepCopyInto(&out.To)
+	if in.QualityGate != nil {
+		in, out := &in.QualityGate, &out.QualityGate
+		*out = new(TupWasQualityGate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -249,6 +495,16 @@ func (in *TupWASStatus) DeepCopyInto(out *TupWASStatus) {
 		in, out := &in.LastAnalyzeCompletionTime, &out.LastAnalyzeCompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.AnalyzeSummary != nil {
+		in, out := &in.AnalyzeSummary, &out.AnalyzeSummary
+		*out = new(AnalyzeSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AnalyzedSource != nil {
+		in, out := &in.AnalyzedSource, &out.AnalyzedSource
+		*out = new(AnalyzedSource)
+		**out = **in
+	}
 	if in.LastBuildStartTime != nil {
 		in, out := &in.LastBuildStartTime, &out.LastBuildStartTime
 		*out = (*in).DeepCopy()
@@ -257,6 +513,34 @@ func (in *TupWASStatus) DeepCopyInto(out *TupWASStatus) {
 		in, out := &in.LastBuildCompletionTime, &out.LastBuildCompletionTime
 		*out = (*in).DeepCopy()
 	}
+	if in.AnalyzeHistory != nil {
+		in, out := &in.AnalyzeHistory, &out.AnalyzeHistory
+		*out = make([]PipelineRunHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.BuildHistory != nil {
+		in, out := &in.BuildHistory, &out.BuildHistory
+		*out = make([]PipelineRunHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ImageHistory != nil {
+		in, out := &in.ImageHistory, &out.ImageHistory
+		*out = make([]ImageHistory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = make([]TaskProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]status.Condition, len(*in))
@@ -269,6 +553,11 @@ func (in *TupWASStatus) DeepCopyInto(out *TupWASStatus) {
 		*out = new(EditorStatus)
 		**out = **in
 	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -282,10 +571,72 @@ func (in *TupWASStatus) DeepCopy() *TupWASStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupWasAutoscaling) DeepCopyInto(out *TupWasAutoscaling) {
+	*out = *in
+	if in.MinReplicas != nil {
+		in, out := &in.MinReplicas, &out.MinReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetCPUUtilizationPercentage != nil {
+		in, out := &in.TargetCPUUtilizationPercentage, &out.TargetCPUUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TargetMemoryUtilizationPercentage != nil {
+		in, out := &in.TargetMemoryUtilizationPercentage, &out.TargetMemoryUtilizationPercentage
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupWasAutoscaling.
+func (in *TupWasAutoscaling) DeepCopy() *TupWasAutoscaling {
+	if in == nil {
+		return nil
+	}
+	out := new(TupWasAutoscaling)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupWasCanary) DeepCopyInto(out *TupWasCanary) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DurationSeconds != nil {
+		in, out := &in.DurationSeconds, &out.DurationSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupWasCanary.
+func (in *TupWasCanary) DeepCopy() *TupWasCanary {
+	if in == nil {
+		return nil
+	}
+	out := new(TupWasCanary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupWasFrom) DeepCopyInto(out *TupWasFrom) {
 	*out = *in
-	out.Git = in.Git
+	in.Git.DeepCopyInto(&out.Git)
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(TupWasWebhook)
+		**out = **in
+	}
 	return
 }
 
@@ -302,6 +653,11 @@ func (in *TupWasFrom) DeepCopy() *TupWasFrom {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupWasGit) DeepCopyInto(out *TupWasGit) {
 	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	return
 }
 
@@ -315,6 +671,37 @@ func (in *TupWasGit) DeepCopy() *TupWasGit {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupWasHealth) DeepCopyInto(out *TupWasHealth) {
+	*out = *in
+	if in.Port != nil {
+		in, out := &in.Port, &out.Port
+		*out = new(int32)
+		**out = **in
+	}
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupWasHealth.
+func (in *TupWasHealth) DeepCopy() *TupWasHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(TupWasHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupWasImage) DeepCopyInto(out *TupWasImage) {
 	*out = *in
@@ -331,10 +718,96 @@ func (in *TupWasImage) DeepCopy() *TupWasImage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupWasQualityGate) DeepCopyInto(out *TupWasQualityGate) {
+	*out = *in
+	if in.MaxMandatoryIssues != nil {
+		in, out := &in.MaxMandatoryIssues, &out.MaxMandatoryIssues
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxStoryPoints != nil {
+		in, out := &in.MaxStoryPoints, &out.MaxStoryPoints
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupWasQualityGate.
+func (in *TupWasQualityGate) DeepCopy() *TupWasQualityGate {
+	if in == nil {
+		return nil
+	}
+	out := new(TupWasQualityGate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TupWasTo) DeepCopyInto(out *TupWasTo) {
 	*out = *in
 	out.Image = in.Image
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Volumes != nil {
+		in, out := &in.Volumes, &out.Volumes
+		*out = make([]TupWasVolume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.NodeSelector != nil {
+		in, out := &in.NodeSelector, &out.NodeSelector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Tolerations != nil {
+		in, out := &in.Tolerations, &out.Tolerations
+		*out = make([]corev1.Toleration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Health != nil {
+		in, out := &in.Health, &out.Health
+		*out = new(TupWasHealth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(TupWasAutoscaling)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(TupWasCanary)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -347,3 +820,72 @@ func (in *TupWasTo) DeepCopy() *TupWasTo {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupWasVolume) DeepCopyInto(out *TupWasVolume) {
+	*out = *in
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(corev1.ConfigMapVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Secret != nil {
+		in, out := &in.Secret, &out.Secret
+		*out = new(corev1.SecretVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaim != nil {
+		in, out := &in.PersistentVolumeClaim, &out.PersistentVolumeClaim
+		*out = new(corev1.PersistentVolumeClaimVolumeSource)
+		**out = **in
+	}
+	if in.EmptyDir != nil {
+		in, out := &in.EmptyDir, &out.EmptyDir
+		*out = new(corev1.EmptyDirVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupWasVolume.
+func (in *TupWasVolume) DeepCopy() *TupWasVolume {
+	if in == nil {
+		return nil
+	}
+	out := new(TupWasVolume)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TupWasWebhook) DeepCopyInto(out *TupWasWebhook) {
+	*out = *in
+	out.SecretRef = in.SecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TupWasWebhook.
+func (in *TupWasWebhook) DeepCopy() *TupWasWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(TupWasWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WasAnalyzeParam) DeepCopyInto(out *WasAnalyzeParam) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WasAnalyzeParam.
+func (in *WasAnalyzeParam) DeepCopy() *WasAnalyzeParam {
+	if in == nil {
+		return nil
+	}
+	out := new(WasAnalyzeParam)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,172 @@
+package v1beta2
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Conversion between v1 (the storage version) and v1beta2, called by the conversion webhook of the operator
+// It is a pure field mapping - inline passwords of v1 are not shown in v1beta2, and are moved to Secrets by the TupDB controller
+// Until they are moved, they are kept in annotations of v1beta2, not to be lost by read-modify-write of v1beta2 clients
+
+// Convert v1 TupWAS to v1beta2
+func (t *TupWAS) ConvertFrom(src *v1.TupWAS) {
+	src = src.DeepCopy()
+	t.TypeMeta = src.TypeMeta
+	t.APIVersion = SchemeGroupVersion.String()
+	t.ObjectMeta = src.ObjectMeta
+	t.Spec = src.Spec
+
+	s := src.Status
+	t.Status = TupWASStatus{
+		ObservedGeneration:        s.ObservedGeneration,
+		LastAnalyzeStartTime:      s.LastAnalyzeStartTime,
+		LastAnalyzeCompletionTime: s.LastAnalyzeCompletionTime,
+		LastAnalyzeResult:         s.LastAnalyzeResult,
+		AnalyzeSummary:            s.AnalyzeSummary,
+		AnalyzedSource:            s.AnalyzedSource,
+		ReportStale:               s.ReportStale,
+		LastBuildStartTime:        s.LastBuildStartTime,
+		LastBuildCompletionTime:   s.LastBuildCompletionTime,
+		LastBuildResult:           s.LastBuildResult,
+		AnalyzePipelineRunName:    s.AnalyzePipelineRunName,
+		BuildPipelineRunName:      s.BuildPipelineRunName,
+		AnalyzeHistory:            s.AnalyzeHistory,
+		BuildHistory:              s.BuildHistory,
+		ImageHistory:              s.ImageHistory,
+		Progress:                  s.Progress,
+		Conditions:                s.Conditions,
+		ReportUrl:                 s.ReportUrl,
+		WasUrl:                    s.WasUrl,
+		CurrentReplicas:           s.CurrentReplicas,
+		DesiredReplicas:           s.DesiredReplicas,
+		Rollout:                   s.Rollout,
+	}
+	// Access code itself is not kept, the controller fills in the Secret name
+	if s.Editor != nil {
+		t.Status.Editor = &EditorStatus{Url: s.Editor.Url, PasswordSecret: s.Editor.PasswordSecret}
+	}
+}
+
+// Convert v1beta2 TupWAS to v1
+func (t *TupWAS) ConvertTo(dst *v1.TupWAS) {
+	src := t.DeepCopy()
+	dst.TypeMeta = src.TypeMeta
+	dst.APIVersion = v1.SchemeGroupVersion.String()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = src.Spec
+
+	s := src.Status
+	dst.Status = v1.TupWASStatus{
+		ObservedGeneration:        s.ObservedGeneration,
+		LastAnalyzeStartTime:      s.LastAnalyzeStartTime,
+		LastAnalyzeCompletionTime: s.LastAnalyzeCompletionTime,
+		LastAnalyzeResult:         s.LastAnalyzeResult,
+		AnalyzeSummary:            s.AnalyzeSummary,
+		AnalyzedSource:            s.AnalyzedSource,
+		ReportStale:               s.ReportStale,
+		LastBuildStartTime:        s.LastBuildStartTime,
+		LastBuildCompletionTime:   s.LastBuildCompletionTime,
+		LastBuildResult:           s.LastBuildResult,
+		AnalyzePipelineRunName:    s.AnalyzePipelineRunName,
+		BuildPipelineRunName:      s.BuildPipelineRunName,
+		AnalyzeHistory:            s.AnalyzeHistory,
+		BuildHistory:              s.BuildHistory,
+		ImageHistory:              s.ImageHistory,
+		Progress:                  s.Progress,
+		Conditions:                s.Conditions,
+		ReportUrl:                 s.ReportUrl,
+		WasUrl:                    s.WasUrl,
+		CurrentReplicas:           s.CurrentReplicas,
+		DesiredReplicas:           s.DesiredReplicas,
+		Rollout:                   s.Rollout,
+	}
+	if s.Editor != nil {
+		dst.Status.Editor = &v1.EditorStatus{Url: s.Editor.Url, PasswordSecret: s.Editor.PasswordSecret}
+	}
+}
+
+// Convert v1 TupDB to v1beta2
+func (t *TupDB) ConvertFrom(src *v1.TupDB) {
+	src = src.DeepCopy()
+	t.TypeMeta = src.TypeMeta
+	t.APIVersion = SchemeGroupVersion.String()
+	t.ObjectMeta = src.ObjectMeta
+	t.Status = src.Status
+
+	from, to := src.Spec.From, src.Spec.To
+	t.Spec = TupDBSpec{
+		From: TupDBFrom{
+			Type:              from.Type,
+			Host:              from.Host,
+			Port:              from.Port,
+			User:              from.User,
+			PasswordSecretRef: from.PasswordSecretRef,
+			Sid:               from.Sid,
+		},
+		To: TupDBTo{
+			Type:              to.Type,
+			StorageSize:       to.StorageSize,
+			User:              to.User,
+			PasswordSecretRef: to.PasswordSecretRef,
+			Sid:               to.Sid,
+		},
+	}
+
+	// Inline passwords not moved to Secrets yet
+	if from.Password != "" && from.PasswordSecretRef == nil {
+		setAnnotation(&t.ObjectMeta, v1.DBAnnotationSourcePassword, from.Password)
+	}
+	if to.Password != "" && to.PasswordSecretRef == nil {
+		setAnnotation(&t.ObjectMeta, v1.DBAnnotationTargetPassword, to.Password)
+	}
+}
+
+// Convert v1beta2 TupDB to v1
+func (t *TupDB) ConvertTo(dst *v1.TupDB) {
+	src := t.DeepCopy()
+	dst.TypeMeta = src.TypeMeta
+	dst.APIVersion = v1.SchemeGroupVersion.String()
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Status = src.Status
+
+	from, to := src.Spec.From, src.Spec.To
+	dst.Spec = v1.TupDBSpec{
+		From: v1.TupDBFrom{
+			Type:              from.Type,
+			Host:              from.Host,
+			Port:              from.Port,
+			User:              from.User,
+			PasswordSecretRef: from.PasswordSecretRef,
+			Sid:               from.Sid,
+		},
+		To: v1.TupDBTo{
+			Type:              to.Type,
+			StorageSize:       to.StorageSize,
+			User:              to.User,
+			PasswordSecretRef: to.PasswordSecretRef,
+			Sid:               to.Sid,
+		},
+	}
+	// Restore inline passwords not moved to Secrets yet, unless Secrets are given in v1beta2
+	if password, exist := dst.Annotations[v1.DBAnnotationSourcePassword]; exist {
+		if dst.Spec.From.PasswordSecretRef == nil {
+			dst.Spec.From.Password = password
+		}
+		delete(dst.Annotations, v1.DBAnnotationSourcePassword)
+	}
+	if password, exist := dst.Annotations[v1.DBAnnotationTargetPassword]; exist {
+		if dst.Spec.To.PasswordSecretRef == nil {
+			dst.Spec.To.Password = password
+		}
+		delete(dst.Annotations, v1.DBAnnotationTargetPassword)
+	}
+}
+
+func setAnnotation(meta *metav1.ObjectMeta, key, value string) {
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[key] = value
+}
@@ -0,0 +1,88 @@
+package v1beta2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Types not changed from v1
+type (
+	TupDBStatus = v1.TupDBStatus
+)
+
+// TupDBSpec defines the desired state of TupDB
+type TupDBSpec struct {
+	// DB Source configuration
+	From TupDBFrom `json:"from"`
+
+	// DB destination configuration
+	To TupDBTo `json:"to"`
+}
+
+type TupDBFrom struct {
+	// Current DB Type
+	// +kubebuilder:validation:Enum=oracle
+	Type string `json:"type,omitempty"`
+
+	// Current DB host
+	// +kubebuilder:validation:Pattern=(([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]*[a-zA-Z0-9])\.)*([A-Za-z0-9]|[A-Za-z0-9][A-Za-z0-9\-]*[A-Za-z0-9])
+	Host string `json:"host,omitempty"`
+
+	// Current DB port
+	Port int32 `json:"port,omitempty"`
+
+	// Current DB user
+	User string `json:"user,omitempty"`
+
+	// Secret key containing the current DB password
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Current DB SID
+	Sid string `json:"sid,omitempty"`
+}
+
+type TupDBTo struct {
+	// Target DB type, to be migrated
+	// +kubebuilder:validation:Enum=tibero
+	Type string `json:"type,omitempty"`
+
+	// Storage size of target DB
+	StorageSize string `json:"storageSize,omitempty"`
+
+	// User for target DB
+	User string `json:"user,omitempty"`
+
+	// Secret key containing the password for target DB
+	PasswordSecretRef *corev1.SecretKeySelector `json:"passwordSecretRef,omitempty"`
+
+	// Current DB SID
+	Sid string `json:"sid,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TupDB is the Schema for the tupdbs API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=tupdbs,scope=Namespaced
+type TupDB struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TupDBSpec   `json:"spec,omitempty"`
+	Status TupDBStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TupDBList contains a list of TupDB
+type TupDBList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TupDB `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TupDB{}, &TupDBList{})
+}
@@ -0,0 +1,125 @@
+package v1beta2
+
+import (
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Types not changed from v1
+type (
+	TupWASSpec         = v1.TupWASSpec
+	AnalyzeSummary     = v1.AnalyzeSummary
+	AnalyzedSource     = v1.AnalyzedSource
+	PipelineRunHistory = v1.PipelineRunHistory
+	ImageHistory       = v1.ImageHistory
+	TaskProgress       = v1.TaskProgress
+	RolloutStatus      = v1.RolloutStatus
+)
+
+// TupWASStatus defines the observed state of TupWAS
+type TupWASStatus struct {
+	// Generation of the spec observed by the controller
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Start time of last analysis
+	LastAnalyzeStartTime *metav1.Time `json:"lastAnalyzeStartTime,omitempty"`
+
+	// Completion time of last analysis
+	LastAnalyzeCompletionTime *metav1.Time `json:"lastAnalyzeCompletionTime,omitempty"`
+
+	// Result of last analysis
+	LastAnalyzeResult string `json:"lastAnalyzeResult,omitempty"`
+
+	// Summary of last analysis report
+	AnalyzeSummary *AnalyzeSummary `json:"analyzeSummary,omitempty"`
+
+	// Source of the last completed analysis, which the report is about
+	AnalyzedSource *AnalyzedSource `json:"analyzedSource,omitempty"`
+
+	// Whether the analysis report is stale, as the source is changed after it is analyzed
+	ReportStale bool `json:"reportStale,omitempty"`
+
+	// Start time of last build
+	LastBuildStartTime *metav1.Time `json:"lastBuildStartTime,omitempty"`
+
+	// Completion time of last build
+	LastBuildCompletionTime *metav1.Time `json:"lastBuildCompletionTime,omitempty"`
+
+	// Result of last build
+	LastBuildResult string `json:"lastBuildResult,omitempty"`
+
+	// PipelineRun name for Analyze
+	AnalyzePipelineRunName string `json:"analyzePipelineRunName,omitempty"`
+
+	// PipelineRun name for Build/Deploy
+	BuildPipelineRunName string `json:"buildPipelineRunName,omitempty"`
+
+	// Analyze PipelineRuns, the latest first
+	AnalyzeHistory []PipelineRunHistory `json:"analyzeHistory,omitempty"`
+
+	// Build/Deploy PipelineRuns, the latest first
+	BuildHistory []PipelineRunHistory `json:"buildHistory,omitempty"`
+
+	// Images built by Build/Deploy PipelineRuns, the latest first
+	ImageHistory []ImageHistory `json:"imageHistory,omitempty"`
+
+	// Progress of each pipeline task, of the latest PipelineRuns
+	Progress []TaskProgress `json:"progress,omitempty"`
+
+	// TupWAS project conditions
+	Conditions []status.Condition `json:"conditions,omitempty"`
+
+	// Editor (VSCode) status
+	Editor *EditorStatus `json:"editor,omitempty"`
+
+	// T-up Jeus URL
+	ReportUrl string `json:"reportUrl,omitempty"`
+
+	// Migrated Was URL
+	WasUrl string `json:"wasUrl,omitempty"`
+
+	// Current number of WAS pods, observed by the autoscaler
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// Desired number of WAS pods, calculated by the autoscaler
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// Rollout of the WAS image
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
+}
+
+type EditorStatus struct {
+	// VSCode URL
+	Url string `json:"url,omitempty"`
+
+	// Name of the Secret containing the VSCode access code, with the key 'password'
+	PasswordSecret string `json:"passwordSecret,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TupWAS is the Schema for the tupwas API
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:path=tupwas,scope=Namespaced
+type TupWAS struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TupWASSpec   `json:"spec,omitempty"`
+	Status TupWASStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TupWASList contains a list of TupWAS
+type TupWASList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TupWAS `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&TupWAS{}, &TupWASList{})
+}
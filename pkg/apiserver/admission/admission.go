@@ -0,0 +1,101 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	"github.com/tmax-cloud/l2c-operator/internal/wrapper"
+)
+
+const (
+	MaxRequestSize = 3 * 1024 * 1024
+)
+
+var log = logf.Log.WithName("admission")
+
+// Admission webhooks, called by the k8s API server with AdmissionReview (admission.k8s.io/v1)
+// URL : /admission/[tupwas|tupdbs]/[mutate|validate]
+func AddAdmissionApis(parent *wrapper.RouterWrapper) error {
+	admissionWrapper := wrapper.New("/admission", nil, nil)
+	if err := parent.Add(admissionWrapper); err != nil {
+		return err
+	}
+
+	handlers := []struct {
+		path    string
+		handler wrapper.HandleFunc
+	}{
+		{path: "/tupwas/mutate", handler: tupWasMutateHandler},
+		{path: "/tupwas/validate", handler: tupWasValidateHandler},
+		{path: "/tupdbs/mutate", handler: tupDBMutateHandler},
+		{path: "/tupdbs/validate", handler: tupDBValidateHandler},
+	}
+	for _, h := range handlers {
+		if err := admissionWrapper.Add(wrapper.New(h.path, []string{"POST"}, h.handler)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type reviewFunc func(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse
+
+// Decode the AdmissionReview, and respond with the response of the review function
+func serveReview(w http.ResponseWriter, req *http.Request, review reviewFunc) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, MaxRequestSize))
+	if err != nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, "cannot read request")
+		return
+	}
+
+	ar := &admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, ar); err != nil || ar.Request == nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, "request is not an AdmissionReview")
+		return
+	}
+
+	resp := review(ar.Request)
+	resp.UID = ar.Request.UID
+
+	_ = utils.RespondJSON(w, &admissionv1.AdmissionReview{
+		TypeMeta: ar.TypeMeta,
+		Response: resp,
+	})
+}
+
+func allowed() *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Allowed: true}
+}
+
+func denied(code int32, msg string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result:  &metav1.Status{Status: metav1.StatusFailure, Code: code, Message: msg},
+	}
+}
+
+func invalid(kind schema.GroupKind, name string, errs field.ErrorList) *admissionv1.AdmissionResponse {
+	status := errors.NewInvalid(kind, name, errs).ErrStatus
+	return &admissionv1.AdmissionResponse{Allowed: false, Result: &status}
+}
+
+// Replace the spec of the object with the defaulted one
+func specPatch(spec interface{}) *admissionv1.AdmissionResponse {
+	patch, err := json.Marshal([]map[string]interface{}{{"op": "replace", "path": "/spec", "value": spec}})
+	if err != nil {
+		return denied(http.StatusInternalServerError, fmt.Sprintf("cannot make patch: %s", err.Error()))
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	return &admissionv1.AdmissionResponse{Allowed: true, Patch: patch, PatchType: &patchType}
+}
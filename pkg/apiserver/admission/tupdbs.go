@@ -0,0 +1,55 @@
+package admission
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+func tupDBMutateHandler(w http.ResponseWriter, req *http.Request) {
+	serveReview(w, req, func(ar *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		tupDB := &tmaxv1.TupDB{}
+		if err := json.Unmarshal(ar.Object.Raw, tupDB); err != nil {
+			return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode TupDB: %s", err.Error()))
+		}
+
+		original := tupDB.Spec.DeepCopy()
+		tupDB.Default()
+		if reflect.DeepEqual(*original, tupDB.Spec) {
+			return allowed()
+		}
+		return specPatch(tupDB.Spec)
+	})
+}
+
+func tupDBValidateHandler(w http.ResponseWriter, req *http.Request) {
+	serveReview(w, req, func(ar *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		tupDB := &tmaxv1.TupDB{}
+		if err := json.Unmarshal(ar.Object.Raw, tupDB); err != nil {
+			return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode TupDB: %s", err.Error()))
+		}
+
+		// Objects being deleted, or not changing the spec (e.g., finalizers by the controller) are not validated
+		if ar.Operation == admissionv1.Update {
+			old := &tmaxv1.TupDB{}
+			if err := json.Unmarshal(ar.OldObject.Raw, old); err != nil {
+				return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode TupDB: %s", err.Error()))
+			}
+			if tupDB.DeletionTimestamp != nil || reflect.DeepEqual(old.Spec, tupDB.Spec) {
+				return allowed()
+			}
+		}
+
+		if errs := tupDB.Validate(); len(errs) != 0 {
+			return invalid(schema.GroupKind{Group: tmaxv1.SchemeGroupVersion.Group, Kind: "TupDB"}, tupDB.Name, errs)
+		}
+
+		return allowed()
+	})
+}
@@ -0,0 +1,92 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+func tupWasMutateHandler(w http.ResponseWriter, req *http.Request) {
+	serveReview(w, req, func(ar *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		tupWas := &tmaxv1.TupWAS{}
+		if err := json.Unmarshal(ar.Object.Raw, tupWas); err != nil {
+			return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode TupWAS: %s", err.Error()))
+		}
+
+		original := tupWas.Spec.DeepCopy()
+		tupWas.Default()
+		if reflect.DeepEqual(*original, tupWas.Spec) {
+			return allowed()
+		}
+		return specPatch(tupWas.Spec)
+	})
+}
+
+func tupWasValidateHandler(w http.ResponseWriter, req *http.Request) {
+	serveReview(w, req, func(ar *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+		tupWas := &tmaxv1.TupWAS{}
+		if err := json.Unmarshal(ar.Object.Raw, tupWas); err != nil {
+			return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode TupWAS: %s", err.Error()))
+		}
+
+		// Objects being deleted, or not changing the spec (e.g., finalizers by the controller) are not validated,
+		// not to block the objects created before the webhook
+		if ar.Operation == admissionv1.Update {
+			old := &tmaxv1.TupWAS{}
+			if err := json.Unmarshal(ar.OldObject.Raw, old); err != nil {
+				return denied(http.StatusBadRequest, fmt.Sprintf("cannot decode TupWAS: %s", err.Error()))
+			}
+			if tupWas.DeletionTimestamp != nil || reflect.DeepEqual(old.Spec, tupWas.Spec) {
+				return allowed()
+			}
+		}
+
+		errs := tupWas.Validate()
+		if tupWas.Spec.To.Type != "" {
+			exist, err := builderProfileExists(tupWas.Spec.To.Type)
+			if err != nil {
+				// Controller reports it if the profile does not exist, so do not block the request
+				log.Error(err, "cannot list TupBuilderProfiles")
+			} else if !exist {
+				errs = append(errs, field.Invalid(field.NewPath("spec", "to", "type"), tupWas.Spec.To.Type, "there is no TupBuilderProfile for the target type"))
+			}
+		}
+		if len(errs) != 0 {
+			return invalid(schema.GroupKind{Group: tmaxv1.SchemeGroupVersion.Group, Kind: "TupWAS"}, tupWas.Name, errs)
+		}
+
+		return allowed()
+	})
+}
+
+func builderProfileExists(targetType string) (bool, error) {
+	opt := client.Options{}
+	utils.AddSchemes(&opt, tmaxv1.SchemeGroupVersion, &tmaxv1.TupBuilderProfile{}, &tmaxv1.TupBuilderProfileList{})
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		return false, err
+	}
+
+	profiles := &tmaxv1.TupBuilderProfileList{}
+	if err := c.List(context.TODO(), profiles); err != nil {
+		return false, err
+	}
+	for _, p := range profiles.Items {
+		if p.Spec.TargetType == targetType {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
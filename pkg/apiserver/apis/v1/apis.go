@@ -57,6 +57,22 @@ func versionHandler(w http.ResponseWriter, _ *http.Request) {
 			Name:       fmt.Sprintf("%s/run", TupWasKind),
 			Namespaced: true,
 		},
+		{
+			Name:       fmt.Sprintf("%s/cancel", TupWasKind),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/logs", TupWasKind),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/progress", TupWasKind),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/rollback", TupWasKind),
+			Namespaced: true,
+		},
 		{
 			Name:       fmt.Sprintf("%s/analyze", TupDbKind),
 			Namespaced: true,
@@ -65,6 +81,18 @@ func versionHandler(w http.ResponseWriter, _ *http.Request) {
 			Name:       fmt.Sprintf("%s/migrate", TupDbKind),
 			Namespaced: true,
 		},
+		{
+			Name:       fmt.Sprintf("%s/cancel", TupDbKind),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/logs", TupDbKind),
+			Namespaced: true,
+		},
+		{
+			Name:       fmt.Sprintf("%s/progress", TupDbKind),
+			Namespaced: true,
+		},
 	}
 
 	_ = utils.RespondJSON(w, apiResourceList)
@@ -53,10 +53,10 @@ func reviewAccess(w http.ResponseWriter, req *http.Request) error {
 
 	userExtras := getUserExtras(req.Header)
 
-	// URL : /apis/tup.tmax.io/v1/namespaces/<namespace>/[tupwas|tupdbs]/<resource name>/[analyze|run]
+	// URL : /apis/tup.tmax.io/v1/namespaces/<namespace>/[tupwas|tupdbs]/<resource name>/[analyze|run|migrate|cancel|logs|progress|rollback]
 	subPaths := strings.Split(req.URL.Path, "/")
 	if len(subPaths) != 9 {
-		return fmt.Errorf("URL should be in form of '/apis/tup.tmax.io/v1/namespaces/<namespace>/[tupwas|tupdbs]/<resource name>/[analyze|run]'")
+		return fmt.Errorf("URL should be in form of '/apis/tup.tmax.io/v1/namespaces/<namespace>/[tupwas|tupdbs]/<resource name>/<subresource>'")
 	}
 	resource := subPaths[6]
 	subResource := subPaths[8]
@@ -70,6 +70,12 @@ func reviewAccess(w http.ResponseWriter, req *http.Request) error {
 		return fmt.Errorf("")
 	}
 
+	// Read-only subresources (e.g., logs) require get verb
+	verb := "update"
+	if req.Method == http.MethodGet {
+		verb = "get"
+	}
+
 	r := &authorization.SubjectAccessReview{
 		Spec: authorization.SubjectAccessReviewSpec{
 			User:   userName,
@@ -82,7 +88,7 @@ func reviewAccess(w http.ResponseWriter, req *http.Request) error {
 				Version:     ApiVersion,
 				Resource:    resource,
 				Subresource: subResource,
-				Verb:        "update",
+				Verb:        verb,
 			},
 		},
 	}
@@ -0,0 +1,45 @@
+package v1
+
+import (
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	"github.com/tmax-cloud/l2c-operator/pkg/apis"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+const (
+	EventSourceComponent = "l2c-operator-apiserver"
+)
+
+var (
+	recorder     record.EventRecorder
+	recorderErr  error
+	recorderOnce sync.Once
+)
+
+// Record an event on the TupWAS/TupDB for the action requested by the API call, with the requesting user
+func recordRequestEvent(req *http.Request, obj runtime.Object, message string) {
+	recorderOnce.Do(func() {
+		s := runtime.NewScheme()
+		if recorderErr = apis.AddToScheme(s); recorderErr != nil {
+			return
+		}
+		recorder, recorderErr = utils.EventRecorder(s, EventSourceComponent)
+	})
+	if recorderErr != nil {
+		log.Error(recorderErr, "cannot make event recorder")
+		return
+	}
+
+	user, err := getUserName(req.Header)
+	if err != nil {
+		user = "unknown"
+	}
+	recorder.Eventf(obj, corev1.EventTypeNormal, tmaxv1.EventReasonRequested, "%s, requested by %s", message, user)
+}
@@ -0,0 +1,178 @@
+package v1
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	tupwascontroller "github.com/tmax-cloud/l2c-operator/pkg/controller/tupwas"
+)
+
+const (
+	LogQueryFollow = "follow"
+	LogQueryTask   = "task"
+
+	LogPollInterval = 2 * time.Second
+
+	StepContainerPrefix = "step-"
+)
+
+// Latest PipelineRun among the given ones - returns nil if there is no PipelineRun
+func latestPipelineRun(prs []tektonv1.PipelineRun) *tektonv1.PipelineRun {
+	if len(prs) == 0 {
+		return nil
+	}
+	tupwascontroller.SortPipelineRuns(prs)
+	return &prs[0]
+}
+
+// Stream logs of the step containers of the PipelineRun's TaskRuns, in the order of their start time
+// If follow is true, it waits for the TaskRuns to be started until the PipelineRun is completed
+func streamPipelineRunLogs(w http.ResponseWriter, req *http.Request, c client.Client, pr *tektonv1.PipelineRun) {
+	follow := req.URL.Query().Get(LogQueryFollow) == "true"
+	task := req.URL.Query().Get(LogQueryTask)
+
+	kubeCli, err := utils.KubeClient()
+	if err != nil {
+		log.Error(err, "cannot get kube client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		_ = utils.RespondError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	streamed := map[string]bool{}
+	for {
+		// Started TaskRuns, which are not streamed yet
+		var taskRuns []*tektonv1.PipelineRunTaskRunStatus
+		for name, tr := range pr.Status.TaskRuns {
+			if streamed[name] || tr.Status == nil || tr.Status.PodName == "" {
+				continue
+			}
+			if task != "" && tr.PipelineTaskName != task {
+				continue
+			}
+			taskRuns = append(taskRuns, tr)
+			streamed[name] = true
+		}
+		sort.Slice(taskRuns, func(i, j int) bool {
+			if taskRuns[i].Status.StartTime == nil || taskRuns[j].Status.StartTime == nil {
+				return taskRuns[j].Status.StartTime == nil
+			}
+			return taskRuns[i].Status.StartTime.Before(taskRuns[j].Status.StartTime)
+		})
+
+		for _, tr := range taskRuns {
+			if err := streamPodLogs(req.Context(), w, flusher, kubeCli, pr.Namespace, tr.Status.PodName, tr.PipelineTaskName, follow); err != nil {
+				log.Error(err, "cannot stream logs")
+				_, _ = fmt.Fprintf(w, "[%s] cannot get logs: %s\n", tr.PipelineTaskName, err.Error())
+				flusher.Flush()
+			}
+		}
+
+		if !follow || pr.Status.CompletionTime != nil {
+			return
+		}
+
+		// Wait for next TaskRuns
+		select {
+		case <-req.Context().Done():
+			return
+		case <-time.After(LogPollInterval):
+		}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: pr.Name, Namespace: pr.Namespace}, pr); err != nil {
+			log.Error(err, "cannot get PipelineRun")
+			return
+		}
+	}
+}
+
+// Stream logs of the step containers of a TaskRun pod, each line prefixed with [<task>:<step>]
+func streamPodLogs(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, kubeCli *kubernetes.Clientset, ns, podName, task string, follow bool) error {
+	pod, err := kubeCli.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, container := range pod.Spec.Containers {
+		if !strings.HasPrefix(container.Name, StepContainerPrefix) {
+			continue
+		}
+		step := strings.TrimPrefix(container.Name, StepContainerPrefix)
+
+		// Container may not be started yet
+		started, err := waitContainerStarted(ctx, kubeCli, ns, podName, container.Name, follow)
+		if err != nil {
+			return err
+		}
+		if !started {
+			continue
+		}
+
+		stream, err := kubeCli.CoreV1().Pods(ns).GetLogs(podName, &corev1.PodLogOptions{Container: container.Name, Follow: follow}).Stream()
+		if err != nil {
+			return err
+		}
+
+		scanner := bufio.NewScanner(stream)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if _, err := fmt.Fprintf(w, "[%s:%s] %s\n", task, step, scanner.Text()); err != nil {
+				_ = stream.Close()
+				return nil
+			}
+			flusher.Flush()
+		}
+		_ = stream.Close()
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Check if the container is started (running or terminated) - waits for it if follow is true
+func waitContainerStarted(ctx context.Context, kubeCli *kubernetes.Clientset, ns, podName, containerName string, follow bool) (bool, error) {
+	for {
+		pod, err := kubeCli.CoreV1().Pods(ns).Get(podName, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, s := range pod.Status.ContainerStatuses {
+			if s.Name == containerName && (s.State.Running != nil || s.State.Terminated != nil) {
+				return true, nil
+			}
+		}
+		if !follow || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			return false, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-time.After(LogPollInterval):
+		}
+	}
+}
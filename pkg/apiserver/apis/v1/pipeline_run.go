@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"context"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Cancel the PipelineRun if it is running - returns true if it is cancelled
+func cancelPipelineRun(c client.Client, pr *tektonv1.PipelineRun) (bool, error) {
+	if pr.Status.CompletionTime != nil || pr.IsCancelled() {
+		return false, nil
+	}
+
+	original := pr.DeepCopy()
+	pr.Spec.Status = tektonv1.PipelineRunSpecStatusCancelled
+	if err := c.Patch(context.TODO(), pr, client.MergeFrom(original)); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Update the status of the object (TupWAS/TupDB) changed by setStatus, which is applied again to the latest object on conflicts
+func updateStatusWithRetry(c client.Client, key types.NamespacedName, obj runtime.Object, setStatus func()) error {
+	latest := true
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !latest {
+			if err := c.Get(context.TODO(), key, obj); err != nil {
+				return err
+			}
+		}
+		latest = false
+		setStatus()
+		return c.Status().Update(context.TODO(), obj)
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strings"
 )
 
 const (
@@ -41,6 +42,18 @@ func AddTupDBApis(parent *wrapper.RouterWrapper) error {
 		return err
 	}
 
+	if err := addTupDBCancelApi(tupDBWrapper); err != nil {
+		return err
+	}
+
+	if err := addTupDBLogsApi(tupDBWrapper); err != nil {
+		return err
+	}
+
+	if err := addTupDBProgressApi(tupDBWrapper); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -61,6 +74,30 @@ func addTupDBMigrateApi(parent *wrapper.RouterWrapper) error {
 	return nil
 }
 
+func addTupDBCancelApi(parent *wrapper.RouterWrapper) error {
+	cancelWrapper := wrapper.New("/cancel", []string{"PUT"}, tupDBCancelHandler)
+	if err := parent.Add(cancelWrapper); err != nil {
+		return err
+	}
+	return nil
+}
+
+func addTupDBLogsApi(parent *wrapper.RouterWrapper) error {
+	logsWrapper := wrapper.New("/logs", []string{"GET"}, tupDBLogsHandler)
+	if err := parent.Add(logsWrapper); err != nil {
+		return err
+	}
+	return nil
+}
+
+func addTupDBProgressApi(parent *wrapper.RouterWrapper) error {
+	progressWrapper := wrapper.New("/progress", []string{"GET"}, tupDBProgressHandler)
+	if err := parent.Add(progressWrapper); err != nil {
+		return err
+	}
+	return nil
+}
+
 func tupDBAnalyzeHandler(w http.ResponseWriter, req *http.Request) {
 	tupDBApiHandler(w, req, TupDBApiTypeAnalyze)
 }
@@ -148,6 +185,204 @@ func tupDBApiHandler(w http.ResponseWriter, req *http.Request, apiType ApiType)
 		return
 	}
 
+	recordRequestEvent(req, tupDB, msg)
 	_ = utils.RespondJSON(w, map[string]string{"message": msg})
 	log.Info(fmt.Sprintf("Created pipelineRun %s/%s", pipelineRun.Namespace, pipelineRun.Name))
 }
+
+func tupDBCancelHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	namespace, namespaceExist := vars["namespace"]
+	tupDBName, nameExist := vars["tupName"]
+	if !namespaceExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+	logger := utils.GetTupLogger(tmaxv1.TupDB{}, namespace, tupDBName)
+	logger.Info("Api Handler came", "Type", "cancel")
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupDB{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "Add scheme error")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupDB := &tmaxv1.TupDB{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: tupDBName, Namespace: namespace}, tupDB); err != nil {
+		logger.Error(err, "cannot get tupDB")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("There is no TupDB %s/%s", namespace, tupDBName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupDB")
+		}
+		return
+	}
+
+	// Cancel analyze/migrate PipelineRuns (in this order), if running
+	pipelineRuns := []struct {
+		name    string
+		condKey status.ConditionType
+	}{
+		{name: tupDB.GenAnalyzePipelineName(), condKey: tmaxv1.DBConditionKeyDBAnalyzing},
+		{name: tupDB.GenMigratePipelineName(), condKey: tmaxv1.DBConditionKeyDBMigrating},
+	}
+	var cancelled []string
+	condKeys := map[string]status.ConditionType{}
+	for _, p := range pipelineRuns {
+		prName := p.name
+		pr := &tektonv1.PipelineRun{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: prName, Namespace: namespace}, pr); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			logger.Error(err, "cannot get PipelineRun")
+			_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("cannot get PipelineRun %s", prName))
+			return
+		}
+		ok, err := cancelPipelineRun(c, pr)
+		if err != nil {
+			logger.Error(err, "cannot cancel PipelineRun")
+			_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("cannot cancel PipelineRun %s", prName))
+			return
+		}
+		if !ok {
+			continue
+		}
+		cancelled = append(cancelled, prName)
+		condKeys[prName] = p.condKey
+	}
+
+	if len(cancelled) == 0 {
+		_ = utils.RespondError(w, http.StatusAccepted, "there is no running PipelineRun")
+		return
+	}
+
+	if err := updateStatusWithRetry(c, types.NamespacedName{Name: tupDBName, Namespace: namespace}, tupDB, func() {
+		for _, prName := range cancelled {
+			tupDB.Status.Conditions = tupDB.Status.SetCondition(condKeys[prName], corev1.ConditionFalse, string(tektonv1.PipelineRunReasonCancelled), fmt.Sprintf("PipelineRun %s is cancelled", prName))
+		}
+	}); err != nil {
+		logger.Error(err, "cannot update tupDB status")
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("PipelineRun %s is cancelled, but cannot update tupDB status", strings.Join(cancelled, ", ")))
+		return
+	}
+
+	msg := fmt.Sprintf("tupDB %s has cancelled PipelineRun %s", tupDB.Name, strings.Join(cancelled, ", "))
+	recordRequestEvent(req, tupDB, msg)
+	_ = utils.RespondJSON(w, map[string]string{"message": msg})
+	log.Info(fmt.Sprintf("Cancelled pipelineRun %s/%s", namespace, strings.Join(cancelled, ", ")))
+}
+
+func tupDBLogsHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	namespace, namespaceExist := vars["namespace"]
+	tupDBName, nameExist := vars["tupName"]
+	if !namespaceExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+	logger := utils.GetTupLogger(tmaxv1.TupDB{}, namespace, tupDBName)
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupDB{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "Add scheme error")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupDB := &tmaxv1.TupDB{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: tupDBName, Namespace: namespace}, tupDB); err != nil {
+		logger.Error(err, "cannot get tupDB")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("There is no TupDB %s/%s", namespace, tupDBName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupDB")
+		}
+		return
+	}
+
+	// Logs of the latest PipelineRun among analyze/migrate
+	var prs []tektonv1.PipelineRun
+	for _, prName := range []string{tupDB.GenAnalyzePipelineName(), tupDB.GenMigratePipelineName()} {
+		pr := &tektonv1.PipelineRun{}
+		if err := c.Get(context.TODO(), types.NamespacedName{Name: prName, Namespace: namespace}, pr); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			logger.Error(err, "cannot get PipelineRun")
+			_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("cannot get PipelineRun %s", prName))
+			return
+		}
+		prs = append(prs, *pr)
+	}
+	pr := latestPipelineRun(prs)
+	if pr == nil {
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no PipelineRun for TupDB %s/%s", namespace, tupDBName))
+		return
+	}
+
+	streamPipelineRunLogs(w, req, c, pr)
+}
+
+func tupDBProgressHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	namespace, namespaceExist := vars["namespace"]
+	tupDBName, nameExist := vars["tupName"]
+	if !namespaceExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+	logger := utils.GetTupLogger(tmaxv1.TupDB{}, namespace, tupDBName)
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupDB{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "Add scheme error")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupDB := &tmaxv1.TupDB{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: tupDBName, Namespace: namespace}, tupDB); err != nil {
+		logger.Error(err, "cannot get tupDB")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("There is no TupDB %s/%s", namespace, tupDBName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupDB")
+		}
+		return
+	}
+
+	progress := tupDB.Status.Progress
+	if progress == nil {
+		progress = []tmaxv1.TaskProgress{}
+	}
+	_ = utils.RespondJSON(w, progress)
+}
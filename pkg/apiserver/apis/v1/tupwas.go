@@ -18,6 +18,9 @@ import (
 	"k8s.io/apimachinery/pkg/types"
 	"net/http"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 type ApiType string
@@ -41,6 +44,18 @@ func AddTupWasApis(parent *wrapper.RouterWrapper) error {
 	if err := addTupWasRunApi(tupWasWrapper); err != nil {
 		return err
 	}
+	if err := addTupWasCancelApi(tupWasWrapper); err != nil {
+		return err
+	}
+	if err := addTupWasLogsApi(tupWasWrapper); err != nil {
+		return err
+	}
+	if err := addTupWasProgressApi(tupWasWrapper); err != nil {
+		return err
+	}
+	if err := addTupWasRollbackApi(tupWasWrapper); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -62,6 +77,42 @@ func addTupWasRunApi(parent *wrapper.RouterWrapper) error {
 	return nil
 }
 
+func addTupWasCancelApi(parent *wrapper.RouterWrapper) error {
+	cancelWrapper := wrapper.New("/cancel", []string{"PUT"}, tupWasCancelHandler)
+	if err := parent.Add(cancelWrapper); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addTupWasLogsApi(parent *wrapper.RouterWrapper) error {
+	logsWrapper := wrapper.New("/logs", []string{"GET"}, tupWasLogsHandler)
+	if err := parent.Add(logsWrapper); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addTupWasProgressApi(parent *wrapper.RouterWrapper) error {
+	progressWrapper := wrapper.New("/progress", []string{"GET"}, tupWasProgressHandler)
+	if err := parent.Add(progressWrapper); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func addTupWasRollbackApi(parent *wrapper.RouterWrapper) error {
+	rollbackWrapper := wrapper.New("/rollback", []string{"PUT"}, tupWasRollbackHandler)
+	if err := parent.Add(rollbackWrapper); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func tupWasAnalyzeHandler(w http.ResponseWriter, req *http.Request) {
 	tupWasApiHandler(w, req, ApiTypeAnalyze)
 }
@@ -114,8 +165,16 @@ func tupWasApiHandler(w http.ResponseWriter, req *http.Request, apiType ApiType)
 	switch apiType {
 	case ApiTypeAnalyze:
 		cond, condFound = tupWas.Status.GetCondition(tmaxv1.WasConditionKeyProjectAnalyzing)
-		pr = tupwascontroller.AnalyzePipelineRun(tupWas)
+		pr, err = tupwascontroller.AnalyzePipelineRun(tupWas)
+		if err != nil {
+			_ = utils.RespondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		msg = fmt.Sprintf("tupWas %s has started analyzing", tupWas.Name)
+		// Mark it to be built/deployed after the analysis, according to spec.autoRun
+		if tupWas.IsBuildDeployAfterAnalysis(false) {
+			tupwascontroller.MarkBuildDeployAfter(pr)
+		}
 
 		// Check if TupWAS project is ready, if not, return error
 		readyCond, ok := tupWas.Status.GetCondition(tmaxv1.WasConditionKeyProjectReady)
@@ -133,6 +192,12 @@ func tupWasApiHandler(w http.ResponseWriter, req *http.Request, apiType ApiType)
 			_ = utils.RespondError(w, http.StatusAccepted, "TupWAS is not analyzed successfully yet")
 			return
 		}
+
+		// Check if quality gate is passed
+		if passed, violation := tupWas.EvaluateQualityGate(); !passed {
+			_ = utils.RespondError(w, http.StatusAccepted, fmt.Sprintf("TupWAS does not pass the quality gate: %s", violation))
+			return
+		}
 	default:
 		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("api type %s is not supported", string(apiType)))
 		return
@@ -156,11 +221,294 @@ func tupWasApiHandler(w http.ResponseWriter, req *http.Request, apiType ApiType)
 		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot make new scheme")
 		return
 	}
-	if err := utils.CheckAndCreateObject(pr, tupWas, c, s, true); err != nil {
+	if err := utils.CreateObject(pr, tupWas, c, s); err != nil {
+		_ = utils.RespondError(w, http.StatusAccepted, "cannot create PipelineRun")
+		return
+	}
+
+	recordRequestEvent(req, tupWas, msg)
+	_ = utils.RespondJSON(w, map[string]string{"message": msg})
+	log.Info(fmt.Sprintf("Created pipelineRun %s/%s", pr.Namespace, pr.Name))
+}
+
+func tupWasCancelHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars["namespace"]
+	resourceName, nameExist := vars["tupName"]
+	if !nsExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupWAS{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupWas := &tmaxv1.TupWAS{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: ns}, tupWas); err != nil {
+		log.Error(err, "cannot get tupWas")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no TupWAS %s/%s", ns, resourceName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupWas")
+		}
+		return
+	}
+
+	// Cancel all running PipelineRuns of the TupWAS
+	prList := &tektonv1.PipelineRunList{}
+	if err := c.List(context.TODO(), prList, client.InNamespace(ns), client.MatchingLabels(tupWas.GenLabels())); err != nil {
+		log.Error(err, "cannot list PipelineRuns")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot list PipelineRuns")
+		return
+	}
+	var cancelled []string
+	condKeys := map[string]status.ConditionType{}
+	for i := range prList.Items {
+		pr := &prList.Items[i]
+		ok, err := cancelPipelineRun(c, pr)
+		if err != nil {
+			log.Error(err, "cannot cancel PipelineRun")
+			_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("cannot cancel PipelineRun %s", pr.Name))
+			return
+		}
+		if !ok {
+			continue
+		}
+		cancelled = append(cancelled, pr.Name)
+
+		condKey := tmaxv1.WasConditionKeyProjectAnalyzing
+		if pr.Labels[tmaxv1.WasLabelKeyPipeline] == tmaxv1.WasPipelineTypeBuildDeploy {
+			condKey = tmaxv1.WasConditionKeyProjectRunning
+		}
+		condKeys[pr.Name] = condKey
+	}
+
+	if len(cancelled) == 0 {
+		_ = utils.RespondError(w, http.StatusAccepted, "there is no running PipelineRun")
+		return
+	}
+
+	// Conditions are set in the order of the names, not to depend on the order of the list
+	sort.Strings(cancelled)
+	if err := updateStatusWithRetry(c, types.NamespacedName{Name: resourceName, Namespace: ns}, tupWas, func() {
+		for _, prName := range cancelled {
+			tupWas.Status.Conditions = tupWas.Status.SetCondition(condKeys[prName], corev1.ConditionFalse, string(tektonv1.PipelineRunReasonCancelled), fmt.Sprintf("PipelineRun %s is cancelled", prName))
+		}
+	}); err != nil {
+		log.Error(err, "cannot update tupWas status")
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("PipelineRun %s is cancelled, but cannot update tupWas status", strings.Join(cancelled, ", ")))
+		return
+	}
+
+	msg := fmt.Sprintf("tupWas %s has cancelled PipelineRun %s", tupWas.Name, strings.Join(cancelled, ", "))
+	recordRequestEvent(req, tupWas, msg)
+	_ = utils.RespondJSON(w, map[string]string{"message": msg})
+	log.Info(fmt.Sprintf("Cancelled pipelineRun %s/%s", ns, strings.Join(cancelled, ", ")))
+}
+
+// Redeploy an image in status.imageHistory, without building it again
+// Query parameter 'to' is an index of the image history (0 is the latest) or a digest of the image
+func tupWasRollbackHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars["namespace"]
+	resourceName, nameExist := vars["tupName"]
+	if !nsExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	to := req.URL.Query().Get("to")
+	if to == "" {
+		_ = utils.RespondError(w, http.StatusBadRequest, "query parameter 'to' is not given")
+		return
+	}
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupWAS{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupWas := &tmaxv1.TupWAS{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: ns}, tupWas); err != nil {
+		log.Error(err, "cannot get tupWas")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no TupWAS %s/%s", ns, resourceName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupWas")
+		}
+		return
+	}
+
+	image := findHistoryImage(tupWas.Status.ImageHistory, to)
+	if image == nil {
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no image %s in the image history", to))
+		return
+	}
+
+	// Check if build/deploy is running
+	cond, condFound := tupWas.Status.GetCondition(tmaxv1.WasConditionKeyProjectRunning)
+	if !condFound || cond == nil {
+		_ = utils.RespondError(w, http.StatusAccepted, "TupWAS may not be ready yet")
+		return
+	}
+	if cond.Status == corev1.ConditionTrue {
+		_ = utils.RespondError(w, http.StatusAccepted, fmt.Sprintf("TupWAS process is still in condition %s", string(ApiTypeRun)))
+		return
+	}
+
+	// Deployed by the digest if recorded, as the tag may be overwritten by another build
+	imageRef := image.GenImageReference()
+	pr := tupwascontroller.DeployPipelineRun(tupWas, imageRef)
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot make new scheme")
+		return
+	}
+	if err := utils.CreateObject(pr, tupWas, c, s); err != nil {
 		_ = utils.RespondError(w, http.StatusAccepted, "cannot create PipelineRun")
 		return
 	}
 
+	msg := fmt.Sprintf("tupWas %s has started rolling back to %s", tupWas.Name, imageRef)
+	recordRequestEvent(req, tupWas, msg)
 	_ = utils.RespondJSON(w, map[string]string{"message": msg})
 	log.Info(fmt.Sprintf("Created pipelineRun %s/%s", pr.Namespace, pr.Name))
 }
+
+// Image of the history, by the index or the digest (with or without 'sha256:' prefix)
+func findHistoryImage(history []tmaxv1.ImageHistory, to string) *tmaxv1.ImageHistory {
+	if i, err := strconv.Atoi(to); err == nil {
+		if i < 0 || i >= len(history) {
+			return nil
+		}
+		return &history[i]
+	}
+
+	digest := strings.TrimPrefix(to, "sha256:")
+	for i, h := range history {
+		if h.Digest != "" && strings.TrimPrefix(h.Digest, "sha256:") == digest {
+			return &history[i]
+		}
+	}
+	return nil
+}
+
+func tupWasLogsHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars["namespace"]
+	resourceName, nameExist := vars["tupName"]
+	if !nsExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupWAS{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupWas := &tmaxv1.TupWAS{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: ns}, tupWas); err != nil {
+		log.Error(err, "cannot get tupWas")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no TupWAS %s/%s", ns, resourceName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupWas")
+		}
+		return
+	}
+
+	// Logs of the latest PipelineRun
+	prList := &tektonv1.PipelineRunList{}
+	if err := c.List(context.TODO(), prList, client.InNamespace(ns), client.MatchingLabels(tupWas.GenLabels())); err != nil {
+		log.Error(err, "cannot list PipelineRuns")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot list PipelineRuns")
+		return
+	}
+	pr := latestPipelineRun(prList.Items)
+	if pr == nil {
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no PipelineRun for TupWAS %s/%s", ns, resourceName))
+		return
+	}
+
+	streamPipelineRunLogs(w, req, c, pr)
+}
+
+func tupWasProgressHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars["namespace"]
+	resourceName, nameExist := vars["tupName"]
+	if !nsExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupWAS{})
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupWas := &tmaxv1.TupWAS{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: ns}, tupWas); err != nil {
+		log.Error(err, "cannot get tupWas")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no TupWAS %s/%s", ns, resourceName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupWas")
+		}
+		return
+	}
+
+	progress := tupWas.Status.Progress
+	if progress == nil {
+		progress = []tmaxv1.TaskProgress{}
+	}
+	_ = utils.RespondJSON(w, progress)
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/base64"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"time"
 
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/cert"
 	apiregv1 "k8s.io/kube-aggregator/pkg/apis/apiregistration/v1"
@@ -27,11 +32,17 @@ const (
 
 	K8sConfigMapName = "extension-apiserver-authentication"
 	K8sConfigMapKey  = "requestheader-client-ca-file"
+
+	// Name of Mutating/ValidatingWebhookConfigurations for TupWAS/TupDB
+	WebhookConfigurationName = "l2c-operator"
 )
 
+// CRDs converted by the conversion webhook
+var conversionCRDNames = []string{"tupwas.tmax.io", "tupdbs.tmax.io"}
+
 // Create and Store certificates for webhook server
 // server key / server cert is stored as file in CertDir
-// CA bundle is stored in ValidatingWebhookConfigurations
+// CA bundle is stored in ApiService, Mutating/ValidatingWebhookConfigurations and CRDs (for the conversion webhook)
 func createCert(ctx context.Context, client client.Client) error {
 	// Make directory recursively
 	if err := os.MkdirAll(CertDir, os.ModePerm); err != nil {
@@ -74,6 +85,54 @@ func createCert(ctx context.Context, client client.Client) error {
 		return err
 	}
 
+	// Update WebhookConfigurations - admission webhooks are optional, skip them if not deployed
+	mutatingCfg := &admissionregistrationv1.MutatingWebhookConfiguration{}
+	if err := client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, mutatingCfg); err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		for i := range mutatingCfg.Webhooks {
+			mutatingCfg.Webhooks[i].ClientConfig.CABundle = caCrt
+		}
+		if err := client.Update(ctx, mutatingCfg); err != nil {
+			return err
+		}
+	}
+
+	validatingCfg := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := client.Get(ctx, types.NamespacedName{Name: WebhookConfigurationName}, validatingCfg); err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		for i := range validatingCfg.Webhooks {
+			validatingCfg.Webhooks[i].ClientConfig.CABundle = caCrt
+		}
+		if err := client.Update(ctx, validatingCfg); err != nil {
+			return err
+		}
+	}
+
+	// Update CRDs - skip the ones not deployed, or not converted by the webhook
+	for _, name := range conversionCRDNames {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"})
+		if err := client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			if errors.IsNotFound(err) {
+				log.Info(fmt.Sprintf("CRD %s is not found, skipping the CA bundle of its conversion webhook", name))
+				continue
+			}
+			return err
+		}
+		strategy, _, err := unstructured.NestedString(crd.Object, "spec", "conversion", "strategy")
+		if err != nil || strategy != "Webhook" {
+			continue
+		}
+		if err := unstructured.SetNestedField(crd.Object, base64.StdEncoding.EncodeToString(caCrt), "spec", "conversion", "webhook", "clientConfig", "caBundle"); err != nil {
+			return err
+		}
+		if err := client.Update(ctx, crd); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -0,0 +1,120 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	"github.com/tmax-cloud/l2c-operator/internal/wrapper"
+)
+
+const (
+	MaxRequestSize = 3 * 1024 * 1024
+)
+
+var log = logf.Log.WithName("conversion")
+
+// ConversionReview of apiextensions.k8s.io/v1
+// apiextensions-apiserver is not a dependency of the operator, so only the fields in use are declared here
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	Request         *ConversionRequest  `json:"request,omitempty"`
+	Response        *ConversionResponse `json:"response,omitempty"`
+}
+
+type ConversionRequest struct {
+	UID               types.UID              `json:"uid"`
+	DesiredAPIVersion string                 `json:"desiredAPIVersion"`
+	Objects           []runtime.RawExtension `json:"objects"`
+}
+
+type ConversionResponse struct {
+	UID              types.UID              `json:"uid"`
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	Result           metav1.Status          `json:"result"`
+}
+
+type convertFunc func(raw []byte, fromVersion, toVersion string) (interface{}, error)
+
+var convertFuncs = map[string]convertFunc{
+	"TupWAS": convertTupWAS,
+	"TupDB":  convertTupDB,
+}
+
+// Conversion webhook for TupWAS/TupDB, called by the k8s API server with ConversionReview
+// URL : /conversion
+func AddConversionApis(parent *wrapper.RouterWrapper) error {
+	return parent.Add(wrapper.New("/conversion", []string{"POST"}, conversionHandler))
+}
+
+func conversionHandler(w http.ResponseWriter, req *http.Request) {
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, MaxRequestSize))
+	if err != nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, "cannot read request")
+		return
+	}
+
+	review := &ConversionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, "request is not a ConversionReview")
+		return
+	}
+
+	resp := &ConversionResponse{UID: review.Request.UID}
+	converted, err := convertObjects(review.Request)
+	if err != nil {
+		log.Error(err, "cannot convert objects")
+		resp.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	} else {
+		resp.ConvertedObjects = converted
+		resp.Result = metav1.Status{Status: metav1.StatusSuccess}
+	}
+
+	_ = utils.RespondJSON(w, &ConversionReview{
+		TypeMeta: review.TypeMeta,
+		Response: resp,
+	})
+}
+
+func convertObjects(req *ConversionRequest) ([]runtime.RawExtension, error) {
+	var converted []runtime.RawExtension
+	for _, obj := range req.Objects {
+		typeMeta := &metav1.TypeMeta{}
+		if err := json.Unmarshal(obj.Raw, typeMeta); err != nil {
+			return nil, err
+		}
+
+		// Objects already in the desired version are returned as they are
+		if typeMeta.APIVersion == req.DesiredAPIVersion {
+			converted = append(converted, obj)
+			continue
+		}
+
+		convert, supported := convertFuncs[typeMeta.Kind]
+		if !supported {
+			return nil, fmt.Errorf("kind %s is not supported", typeMeta.Kind)
+		}
+		result, err := convert(obj.Raw, typeMeta.APIVersion, req.DesiredAPIVersion)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := json.Marshal(result)
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: raw})
+	}
+
+	return converted, nil
+}
+
+func unsupportedVersions(fromVersion, toVersion string) error {
+	return fmt.Errorf("conversion from %s to %s is not supported", fromVersion, toVersion)
+}
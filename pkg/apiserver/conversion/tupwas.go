@@ -0,0 +1,31 @@
+package conversion
+
+import (
+	"encoding/json"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+	tmaxv1beta2 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1beta2"
+)
+
+func convertTupWAS(raw []byte, fromVersion, toVersion string) (interface{}, error) {
+	switch {
+	case fromVersion == tmaxv1.SchemeGroupVersion.String() && toVersion == tmaxv1beta2.SchemeGroupVersion.String():
+		src := &tmaxv1.TupWAS{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, err
+		}
+		dst := &tmaxv1beta2.TupWAS{}
+		dst.ConvertFrom(src)
+		return dst, nil
+	case fromVersion == tmaxv1beta2.SchemeGroupVersion.String() && toVersion == tmaxv1.SchemeGroupVersion.String():
+		src := &tmaxv1beta2.TupWAS{}
+		if err := json.Unmarshal(raw, src); err != nil {
+			return nil, err
+		}
+		dst := &tmaxv1.TupWAS{}
+		src.ConvertTo(dst)
+		return dst, nil
+	default:
+		return nil, unsupportedVersions(fromVersion, toVersion)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"path"
 
 	"github.com/gorilla/mux"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -17,7 +18,9 @@ import (
 
 	"github.com/tmax-cloud/l2c-operator/internal/utils"
 	"github.com/tmax-cloud/l2c-operator/internal/wrapper"
+	"github.com/tmax-cloud/l2c-operator/pkg/apiserver/admission"
 	"github.com/tmax-cloud/l2c-operator/pkg/apiserver/apis"
+	"github.com/tmax-cloud/l2c-operator/pkg/apiserver/conversion"
 )
 
 const (
@@ -44,7 +47,17 @@ func New() *Server {
 		os.Exit(1)
 	}
 
-	// Create CERT & Update Secret/ApiService
+	if err := admission.AddAdmissionApis(server.Wrapper); err != nil {
+		log.Error(err, "cannot add admission apis")
+		os.Exit(1)
+	}
+
+	if err := conversion.AddConversionApis(server.Wrapper); err != nil {
+		log.Error(err, "cannot add conversion apis")
+		os.Exit(1)
+	}
+
+	// Create CERT & Update Secret/ApiService/WebhookConfigurations/CRDs
 	opt := client.Options{}
 	opt.Scheme = runtime.NewScheme()
 	if err := apiregv1.AddToScheme(opt.Scheme); err != nil {
@@ -55,6 +68,10 @@ func New() *Server {
 		log.Error(err, "cannot register scheme")
 		os.Exit(1)
 	}
+	if err := admissionregistrationv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "cannot register scheme")
+		os.Exit(1)
+	}
 
 	server.Client, err = utils.Client(opt)
 	if err != nil {
@@ -0,0 +1,177 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/operator-framework/operator-sdk/pkg/status"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	"github.com/tmax-cloud/l2c-operator/internal/wrapper"
+	"github.com/tmax-cloud/l2c-operator/pkg/apis"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+	tupwascontroller "github.com/tmax-cloud/l2c-operator/pkg/controller/tupwas"
+)
+
+const (
+	MaxPayloadSize = 10 * 1024 * 1024
+)
+
+var log = logf.Log.WithName("webhook")
+
+// URL : /webhook/namespaces/<namespace>/tupwas/<resource name>
+func AddGitWebhookApis(parent *wrapper.RouterWrapper) error {
+	webhookWrapper := wrapper.New("/webhook/namespaces/{namespace}", nil, nil)
+	if err := parent.Add(webhookWrapper); err != nil {
+		return err
+	}
+
+	tupWasWrapper := wrapper.New("/tupwas/{tupName}", []string{"POST"}, tupWasGitWebhookHandler)
+	if err := webhookWrapper.Add(tupWasWrapper); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func tupWasGitWebhookHandler(w http.ResponseWriter, req *http.Request) {
+	vars := mux.Vars(req)
+
+	ns, nsExist := vars["namespace"]
+	resourceName, nameExist := vars["tupName"]
+	if !nsExist || !nameExist {
+		_ = utils.RespondError(w, http.StatusBadRequest, "url is malformed")
+		return
+	}
+
+	body, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, MaxPayloadSize))
+	if err != nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, "cannot read payload")
+		return
+	}
+
+	provider, event, err := detectProvider(req.Header)
+	if err != nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	opt := client.Options{}
+	utils.AddSchemes(&opt, schema.GroupVersion{Group: "tmax.io", Version: "v1"}, &tmaxv1.TupWAS{})
+	if err := corev1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+	if err := tektonv1.AddToScheme(opt.Scheme); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not initialize client")
+		return
+	}
+
+	c, err := utils.Client(opt)
+	if err != nil {
+		log.Error(err, "cannot get client")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "could not make k8s client")
+		return
+	}
+
+	tupWas := &tmaxv1.TupWAS{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: resourceName, Namespace: ns}, tupWas); err != nil {
+		log.Error(err, "cannot get tupWas")
+		if errors.IsNotFound(err) {
+			_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("there is no TupWAS %s/%s", ns, resourceName))
+		} else {
+			_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get tupWas")
+		}
+		return
+	}
+
+	if tupWas.Spec.From.Webhook == nil {
+		_ = utils.RespondError(w, http.StatusNotFound, fmt.Sprintf("webhook is not configured for TupWAS %s/%s", ns, resourceName))
+		return
+	}
+
+	// Verify signature
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: tupWas.Spec.From.Webhook.SecretRef.Name, Namespace: ns}, secret); err != nil {
+		log.Error(err, "cannot get webhook secret")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot get webhook secret")
+		return
+	}
+	token, ok := secret.Data[tmaxv1.WasWebhookSecretKey]
+	if !ok {
+		_ = utils.RespondError(w, http.StatusInternalServerError, fmt.Sprintf("webhook secret does not have key %s", tmaxv1.WasWebhookSecretKey))
+		return
+	}
+	if err := verifySignature(provider, req.Header, body, token); err != nil {
+		_ = utils.RespondError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	// Only push events matching the spec trigger analysis
+	if !isPushEvent(provider, event) {
+		_ = utils.RespondJSON(w, map[string]string{"message": fmt.Sprintf("event %s is ignored", event)})
+		return
+	}
+	pushEvent, err := parsePushEvent(provider, body)
+	if err != nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, fmt.Sprintf("cannot parse payload: %s", err.Error()))
+		return
+	}
+	if !pushEvent.Matches(tupWas.Spec.From.Git.Url, tupWas.GenGitRevision()) {
+		_ = utils.RespondJSON(w, map[string]string{"message": fmt.Sprintf("push to %s does not match the source of TupWAS %s", pushEvent.Ref, tupWas.Name)})
+		return
+	}
+
+	// Check if TupWAS project is ready and not analyzing/running
+	readyCond, ok := tupWas.Status.GetCondition(tmaxv1.WasConditionKeyProjectReady)
+	if !ok || readyCond.Status != corev1.ConditionTrue {
+		_ = utils.RespondError(w, http.StatusAccepted, "TupWAS is not ready yet")
+		return
+	}
+	for _, key := range []status.ConditionType{tmaxv1.WasConditionKeyProjectAnalyzing, tmaxv1.WasConditionKeyProjectRunning} {
+		cond, ok := tupWas.Status.GetCondition(key)
+		if ok && cond.Status == corev1.ConditionTrue {
+			_ = utils.RespondError(w, http.StatusAccepted, fmt.Sprintf("TupWAS process is still in condition %s", string(key)))
+			return
+		}
+	}
+
+	// The analysis clones the head of the revision afresh, i.e., including the pushed commits
+	pr, err := tupwascontroller.AnalyzePipelineRun(tupWas)
+	if err != nil {
+		_ = utils.RespondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	// Mark it to be built/deployed after the analysis
+	if tupWas.IsBuildDeployAfterAnalysis(true) {
+		tupwascontroller.MarkBuildDeployAfter(pr)
+	}
+
+	s := runtime.NewScheme()
+	if err := apis.AddToScheme(s); err != nil {
+		log.Error(err, "")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot make new scheme")
+		return
+	}
+	if err := utils.CreateObject(pr, tupWas, c, s); err != nil {
+		log.Error(err, "cannot create PipelineRun")
+		_ = utils.RespondError(w, http.StatusInternalServerError, "cannot create PipelineRun")
+		return
+	}
+
+	_ = utils.RespondJSON(w, map[string]string{"message": fmt.Sprintf("tupWas %s has started analyzing", tupWas.Name)})
+	log.Info(fmt.Sprintf("Created pipelineRun %s/%s by %s push webhook", pr.Namespace, pr.Name, provider))
+}
@@ -0,0 +1,178 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type GitProvider string
+
+const (
+	GitProviderGithub = GitProvider("github")
+	GitProviderGitlab = GitProvider("gitlab")
+	GitProviderGitea  = GitProvider("gitea")
+)
+
+// Push event, common to all providers
+type PushEvent struct {
+	// Ref pushed (e.g., refs/heads/master)
+	Ref string
+
+	// URLs of the pushed repository (clone urls via https/ssh, web url)
+	RepoUrls []string
+}
+
+type githubPushPayload struct {
+	Ref        string `json:"ref"`
+	Repository struct {
+		CloneUrl string `json:"clone_url"`
+		SshUrl   string `json:"ssh_url"`
+		HtmlUrl  string `json:"html_url"`
+	} `json:"repository"`
+}
+
+type gitlabPushPayload struct {
+	Ref     string `json:"ref"`
+	Project struct {
+		GitHttpUrl string `json:"git_http_url"`
+		GitSshUrl  string `json:"git_ssh_url"`
+		WebUrl     string `json:"web_url"`
+	} `json:"project"`
+}
+
+// Detect git provider from the request header
+// Gitea also sends X-GitHub-Event header, so check Gitea first
+func detectProvider(header http.Header) (GitProvider, string, error) {
+	if e := header.Get("X-Gitea-Event"); e != "" {
+		return GitProviderGitea, e, nil
+	}
+	if e := header.Get("X-Gitlab-Event"); e != "" {
+		return GitProviderGitlab, e, nil
+	}
+	if e := header.Get("X-GitHub-Event"); e != "" {
+		return GitProviderGithub, e, nil
+	}
+	return "", "", fmt.Errorf("cannot detect git provider from the request header")
+}
+
+func isPushEvent(provider GitProvider, event string) bool {
+	switch provider {
+	case GitProviderGithub, GitProviderGitea:
+		return event == "push"
+	case GitProviderGitlab:
+		return event == "Push Hook"
+	default:
+		return false
+	}
+}
+
+// Verify the payload with the webhook secret
+// GitHub/Gitea sign the payload with HMAC, GitLab sends the secret token itself
+func verifySignature(provider GitProvider, header http.Header, body, secret []byte) error {
+	switch provider {
+	case GitProviderGithub:
+		if sig := header.Get("X-Hub-Signature-256"); sig != "" {
+			return verifyHmac(sha256.New, secret, body, strings.TrimPrefix(sig, "sha256="))
+		}
+		if sig := header.Get("X-Hub-Signature"); sig != "" {
+			return verifyHmac(sha1.New, secret, body, strings.TrimPrefix(sig, "sha1="))
+		}
+		return fmt.Errorf("no signature header")
+	case GitProviderGitea:
+		sig := header.Get("X-Gitea-Signature")
+		if sig == "" {
+			return fmt.Errorf("no signature header")
+		}
+		return verifyHmac(sha256.New, secret, body, sig)
+	case GitProviderGitlab:
+		token := header.Get("X-Gitlab-Token")
+		if subtle.ConstantTimeCompare([]byte(token), secret) != 1 {
+			return fmt.Errorf("token is not valid")
+		}
+		return nil
+	default:
+		return fmt.Errorf("git provider %s is not supported", provider)
+	}
+}
+
+func verifyHmac(h func() hash.Hash, secret, body []byte, signature string) error {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("signature is malformed")
+	}
+
+	mac := hmac.New(h, secret)
+	_, _ = mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return fmt.Errorf("signature is not valid")
+	}
+	return nil
+}
+
+func parsePushEvent(provider GitProvider, body []byte) (*PushEvent, error) {
+	switch provider {
+	case GitProviderGithub, GitProviderGitea:
+		payload := &githubPushPayload{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			return nil, err
+		}
+		return &PushEvent{
+			Ref:      payload.Ref,
+			RepoUrls: []string{payload.Repository.CloneUrl, payload.Repository.SshUrl, payload.Repository.HtmlUrl},
+		}, nil
+	case GitProviderGitlab:
+		payload := &gitlabPushPayload{}
+		if err := json.Unmarshal(body, payload); err != nil {
+			return nil, err
+		}
+		return &PushEvent{
+			Ref:      payload.Ref,
+			RepoUrls: []string{payload.Project.GitHttpUrl, payload.Project.GitSshUrl, payload.Project.WebUrl},
+		}, nil
+	default:
+		return nil, fmt.Errorf("git provider %s is not supported", provider)
+	}
+}
+
+// Check if the push event is for the given repository/branch
+func (e *PushEvent) Matches(gitUrl, revision string) bool {
+	if e.Ref != "refs/heads/"+revision {
+		return false
+	}
+
+	target := normalizeGitUrl(gitUrl)
+	for _, u := range e.RepoUrls {
+		if u != "" && normalizeGitUrl(u) == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize git url to be in a form of host/path
+// https://github.com/org/repo.git, git@github.com:org/repo, ssh://git@github.com/org/repo -> github.com/org/repo
+func normalizeGitUrl(gitUrl string) string {
+	s := strings.TrimSpace(gitUrl)
+	if strings.Contains(s, "://") {
+		if u, err := url.Parse(s); err == nil {
+			s = u.Hostname() + u.Path
+		}
+	} else if hostPath := strings.SplitN(s, ":", 2); len(hostPath) == 2 {
+		host := hostPath[0]
+		if i := strings.LastIndex(host, "@"); i >= 0 {
+			host = host[i+1:]
+		}
+		s = host + "/" + strings.TrimPrefix(hostPath[1], "/")
+	}
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "/"), ".git")
+	return strings.ToLower(s)
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"testing"
+)
+
+func sign(h func() hash.Hash, secret, body []byte) string {
+	mac := hmac.New(h, secret)
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("webhook-secret")
+	body := []byte(`{"ref":"refs/heads/master"}`)
+
+	tc := map[string]struct {
+		provider GitProvider
+		header   map[string]string
+		valid    bool
+	}{
+		"githubSha256": {
+			provider: GitProviderGithub,
+			header:   map[string]string{"X-Hub-Signature-256": "sha256=" + sign(sha256.New, secret, body)},
+			valid:    true,
+		},
+		"githubSha1": {
+			provider: GitProviderGithub,
+			header:   map[string]string{"X-Hub-Signature": "sha1=" + sign(sha1.New, secret, body)},
+			valid:    true,
+		},
+		"githubInvalid": {
+			provider: GitProviderGithub,
+			header:   map[string]string{"X-Hub-Signature-256": "sha256=" + sign(sha256.New, []byte("wrong"), body)},
+			valid:    false,
+		},
+		"githubMalformed": {
+			provider: GitProviderGithub,
+			header:   map[string]string{"X-Hub-Signature-256": "sha256=not-hex"},
+			valid:    false,
+		},
+		"githubMissing": {
+			provider: GitProviderGithub,
+			header:   map[string]string{},
+			valid:    false,
+		},
+		"gitea": {
+			provider: GitProviderGitea,
+			header:   map[string]string{"X-Gitea-Signature": sign(sha256.New, secret, body)},
+			valid:    true,
+		},
+		"giteaInvalid": {
+			provider: GitProviderGitea,
+			header:   map[string]string{"X-Gitea-Signature": sign(sha256.New, []byte("wrong"), body)},
+			valid:    false,
+		},
+		"giteaMissing": {
+			provider: GitProviderGitea,
+			header:   map[string]string{},
+			valid:    false,
+		},
+		"gitlab": {
+			provider: GitProviderGitlab,
+			header:   map[string]string{"X-Gitlab-Token": string(secret)},
+			valid:    true,
+		},
+		"gitlabInvalid": {
+			provider: GitProviderGitlab,
+			header:   map[string]string{"X-Gitlab-Token": "wrong"},
+			valid:    false,
+		},
+		"gitlabMissing": {
+			provider: GitProviderGitlab,
+			header:   map[string]string{},
+			valid:    false,
+		},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			header := http.Header{}
+			for k, v := range c.header {
+				header.Set(k, v)
+			}
+			err := verifySignature(c.provider, header, body, secret)
+			if c.valid && err != nil {
+				t.Fatalf("expected valid signature, got error %v", err)
+			}
+			if !c.valid && err == nil {
+				t.Fatal("expected invalid signature, got no error")
+			}
+		})
+	}
+}
+
+func TestNormalizeGitUrl(t *testing.T) {
+	tc := map[string]struct {
+		url      string
+		expected string
+	}{
+		"https":         {url: "https://github.com/org/repo", expected: "github.com/org/repo"},
+		"httpsGit":      {url: "https://github.com/org/repo.git", expected: "github.com/org/repo"},
+		"httpsSlash":    {url: "https://github.com/org/repo/", expected: "github.com/org/repo"},
+		"httpsPort":     {url: "https://gitlab.example.com:8443/org/repo.git", expected: "gitlab.example.com/org/repo"},
+		"httpsUpper":    {url: "https://GitHub.com/Org/Repo", expected: "github.com/org/repo"},
+		"scp":           {url: "git@github.com:org/repo.git", expected: "github.com/org/repo"},
+		"ssh":           {url: "ssh://git@github.com/org/repo", expected: "github.com/org/repo"},
+		"sshPort":       {url: "ssh://git@gitea.example.com:2222/org/repo.git", expected: "gitea.example.com/org/repo"},
+		"surroundSpace": {url: " https://github.com/org/repo ", expected: "github.com/org/repo"},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			if actual := normalizeGitUrl(c.url); actual != c.expected {
+				t.Fatalf("expected %s, got %s", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPushEventMatches(t *testing.T) {
+	e := &PushEvent{
+		Ref:      "refs/heads/master",
+		RepoUrls: []string{"https://github.com/org/repo.git", "git@github.com:org/repo.git", ""},
+	}
+
+	tc := map[string]struct {
+		url      string
+		revision string
+		matches  bool
+	}{
+		"sameRepo":      {url: "https://github.com/org/repo", revision: "master", matches: true},
+		"sshRepo":       {url: "ssh://git@github.com/org/repo", revision: "master", matches: true},
+		"otherBranch":   {url: "https://github.com/org/repo", revision: "develop", matches: false},
+		"otherRepo":     {url: "https://github.com/org/other", revision: "master", matches: false},
+		"otherHostRepo": {url: "https://gitlab.com/org/repo", revision: "master", matches: false},
+	}
+
+	for name, c := range tc {
+		t.Run(name, func(t *testing.T) {
+			if actual := e.Matches(c.url, c.revision); actual != c.matches {
+				t.Fatalf("expected %t, got %t", c.matches, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gorilla/mux"
+
+	"github.com/tmax-cloud/l2c-operator/internal/wrapper"
+)
+
+const (
+	// Port of the git webhook server, served over plain HTTP
+	// It is exposed through an ingress, which terminates TLS with a trusted certificate (see deploy/webhook_ingress.yaml)
+	Port = 24336
+)
+
+// Server serves only the git webhooks, apart from the extension API server, as git servers cannot trust its self-signed certificate
+type Server struct {
+	Wrapper *wrapper.RouterWrapper
+}
+
+func New() *Server {
+	server := &Server{}
+	server.Wrapper = wrapper.New("/", nil, nil)
+	server.Wrapper.Router = mux.NewRouter()
+
+	if err := AddGitWebhookApis(server.Wrapper); err != nil {
+		log.Error(err, "cannot add webhook apis")
+		os.Exit(1)
+	}
+
+	return server
+}
+
+func (s *Server) Start() {
+	addr := fmt.Sprintf("0.0.0.0:%d", Port)
+	log.Info(fmt.Sprintf("Git webhook server is running on %s", addr))
+
+	httpServer := &http.Server{Addr: addr, Handler: s.Wrapper.Router}
+	if err := httpServer.ListenAndServe(); err != nil {
+		log.Error(err, "cannot launch git webhook server")
+		os.Exit(1)
+	}
+}
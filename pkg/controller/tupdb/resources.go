@@ -69,14 +69,12 @@ func MigratePipeline(tupDB *tmaxv1.TupDB) *tektonv1.Pipeline {
 		Spec: tektonv1.PipelineSpec{
 			Params: []tektonv1.ParamSpec{
 				{Name: tmaxv1.DBPipelineParamNameSourceUserName},
-				{Name: tmaxv1.DBPipelineParamNameSourcePassword},
 				{Name: tmaxv1.DBPipelineParamNameSourceType},
 				{Name: tmaxv1.DBPipelineParamNameSourceSID},
 				{Name: tmaxv1.DBPipelineParamNameSourceAs},
 				{Name: tmaxv1.DBPipelineParamNameSourcePort},
 				{Name: tmaxv1.DBPipelineParamNameSourceIP},
 				{Name: tmaxv1.DBPipelineParamNameTargetUserName},
-				{Name: tmaxv1.DBPipelineParamNameTargetPassword},
 				{Name: tmaxv1.DBPipelineParamNameTargetType},
 				{Name: tmaxv1.DBPipelineParamNameTargetSID},
 				{Name: tmaxv1.DBPipelineParamNameTargetPort},
@@ -88,9 +86,8 @@ func MigratePipeline(tupDB *tmaxv1.TupDB) *tektonv1.Pipeline {
 				Name:    tmaxv1.DBPipelineTaskNameMigrateDB,
 				TaskRef: &tektonv1.TaskRef{Name: tmaxv1.TaskNameMigrateDB, Kind: tektonv1.ClusterTaskKind},
 				Params: []tektonv1.Param{{
-					// [TODO] Parameterize
 					Name:  "SECRET_NAME",
-					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "tup-db-secret"},
+					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.GenMigrateSecretName()},
 				}, {
 					Name:  "SOURCE_TYPE",
 					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.DBPipelineParamNameSourceType)},
@@ -143,9 +140,6 @@ func MigratePipelineRun(tupDB *tmaxv1.TupDB) *tektonv1.PipelineRun {
 			Params: []tektonv1.Param{{
 				Name:  tmaxv1.DBPipelineParamNameSourceUserName,
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.Spec.From.User},
-			}, {
-				Name:  tmaxv1.DBPipelineParamNameSourcePassword,
-				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.Spec.From.Password},
 			}, {
 				Name:  tmaxv1.DBPipelineParamNameSourceType,
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.Spec.From.Type},
@@ -164,9 +158,6 @@ func MigratePipelineRun(tupDB *tmaxv1.TupDB) *tektonv1.PipelineRun {
 			}, {
 				Name:  tmaxv1.DBPipelineParamNameTargetUserName,
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.Spec.To.User},
-			}, {
-				Name:  tmaxv1.DBPipelineParamNameTargetPassword,
-				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.Spec.To.Password},
 			}, {
 				Name:  tmaxv1.DBPipelineParamNameTargetType,
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupDB.Spec.To.Type},
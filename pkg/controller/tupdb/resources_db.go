@@ -90,7 +90,7 @@ func tupDBSecret(dbInstance *tmaxv1.TupDB) (*corev1.Secret, error) {
 			Kind:       "Secret",
 		},
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tmaxv1.TupDBSecretName,
+			Name:      dbInstance.GenMigrateSecretName(),
 			Namespace: dbInstance.Namespace,
 		},
 		StringData: secretVal,
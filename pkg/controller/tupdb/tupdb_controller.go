@@ -4,13 +4,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/operator-framework/operator-sdk/pkg/status"
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	"github.com/tmax-cloud/l2c-operator/internal/utils"
 	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
-	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -35,7 +36,7 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileTupDB{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileTupDB{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("tupdb-controller")}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -61,6 +62,13 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
+	err = c.Watch(&source.Kind{Type: &tektonv1.PipelineRun{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &tmaxv1.TupDB{},
+	})
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -72,8 +80,9 @@ var _ reconcile.Reconciler = &ReconcileTupDB{}
 type ReconcileTupDB struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a TupDB object and makes changes based on the state read
@@ -93,7 +102,7 @@ func (r *ReconcileTupDB) Reconcile(request reconcile.Request) (reconcile.Result,
 	if err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Owned objects are automatically garbage collected, and the others are deleted by the finalizer.
 			// Return and don't requeue
 			return reconcile.Result{}, nil
 		}
@@ -103,6 +112,26 @@ func (r *ReconcileTupDB) Reconcile(request reconcile.Request) (reconcile.Result,
 
 	if len(instance.Status.Conditions) == 0 {
 		instance.Status.SetDefaults()
+	} else {
+		instance.Status.SetMissingConditions()
+	}
+
+	// Clean up the resources not owned by the TupDB, before it is deleted
+	if instance.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.finalize(instance)
+	}
+	if err := r.addFinalizer(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Inline passwords are not kept in the TupDB
+	if err := r.migratePasswords(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Watch PipelineRun
+	if err := r.watchPipelineRun(instance); err != nil {
+		return reconcile.Result{}, err
 	}
 	// [TODO] TupDB Analyzer
 
@@ -116,17 +145,28 @@ func (r *ReconcileTupDB) Reconcile(request reconcile.Request) (reconcile.Result,
 	service := &corev1.Service{}
 	_ = r.client.Get(context.TODO(), types.NamespacedName{Name: dbResourceName(instance), Namespace: instance.Namespace}, service)
 
+	prevHost, prevPort := instance.Status.TargetHost, instance.Status.TargetPort
 	if err := updateTupDBStatus(instance, service); err != nil {
 		reqLogger.Error(err, "DB Update failed")
+		if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, "target DB is not ready", err.Error()); err != nil {
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, err
+	}
+	if err := r.setCondition(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionTrue, "", ""); err != nil {
 		return reconcile.Result{}, err
 	}
+	if instance.Status.TargetHost != prevHost || instance.Status.TargetPort != prevPort {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonTargetReady, "Target DB is ready at %s:%d", instance.Status.TargetHost, instance.Status.TargetPort)
+	}
 
 	migratePipeline := MigratePipeline(instance)
-	if err := r.createAndUpdateStatus(migratePipeline, instance, "error getting/creating pipeline"); err != nil {
+	if err := r.applyAndUpdateStatus(migratePipeline, instance, "error getting/creating pipeline"); err != nil {
 		reqLogger.Error(err, "Error occurred")
 		return reconcile.Result{}, err
 	}
 
+	instance.Status.ObservedGeneration = instance.Generation
 	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -150,57 +190,54 @@ func (r *ReconcileTupDB) makeTargetDBReady(instance *tmaxv1.TupDB) error {
 		}
 	}
 	logger.Info("PVC Created")
-	service := &corev1.Service{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dbResourceName(instance), Namespace: instance.Namespace}, service); err != nil {
-		if errors.IsNotFound(err) {
-			service, _ = dbService(instance)
-			if err := r.createAndUpdateStatus(service, instance, "error create Service"); err != nil {
-				return err
-			}
-		} else {
+
+	// Secrets are made with the passwords, read from the referred Secrets
+	withPasswords, err := withPasswords(r.client, instance)
+	if err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, "error reading passwords", err.Error()); err != nil {
 			return err
 		}
+		return err
 	}
-	logger.Info("Service Created")
 
-	secret := &corev1.Secret{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dbResourceName(instance), Namespace: instance.Namespace}, secret); err != nil {
-		if errors.IsNotFound(err) {
-			secret, _ = dbDeploySecret(instance)
-			if err := r.createAndUpdateStatus(secret, nil, "error create Secret"); err != nil {
-				return err
-			}
-			logger.Info("Secret Created")
+	// Others are kept up-to-date with the TupDB
+	service, err := dbService(instance)
+	if err != nil {
+		return err
+	}
+	if err := r.applyAndUpdateStatus(service, instance, "error create Service"); err != nil {
+		return err
+	}
+	logger.Info("Service Created")
 
-		} else {
+	secret, err := dbDeploySecret(withPasswords)
+	if err != nil {
+		return err
+	}
+	// Not owned by the TupDB, deleted by the finalizer
+	if err := utils.CheckAndUpdateObject(secret, nil, r.client, r.scheme); err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, "error create Secret", err.Error()); err != nil {
 			return err
 		}
+		return err
 	}
+	logger.Info("Secret Created")
 
-	tupSecret := &corev1.Secret{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: tmaxv1.TupDBSecretName, Namespace: instance.Namespace}, tupSecret); err != nil {
-		if errors.IsNotFound(err) {
-			secret, _ = tupDBSecret(instance)
-			if err := r.createAndUpdateStatus(secret, instance, "error create Secret"); err != nil {
-				return err
-			}
-			logger.Info("Secret Created")
-
-		} else {
-			return err
-		}
+	tupSecret, err := tupDBSecret(withPasswords)
+	if err != nil {
+		return err
+	}
+	if err := r.applyAndUpdateStatus(tupSecret, instance, "error create Secret"); err != nil {
+		return err
 	}
+	logger.Info("Secret Created")
 
-	deployment := &appsv1.Deployment{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: dbResourceName(instance), Namespace: instance.Namespace}, deployment); err != nil {
-		if errors.IsNotFound(err) {
-			deployment, _ = dbDeploy(instance)
-			if err := r.createAndUpdateStatus(deployment, instance, "error create Deployment"); err != nil {
-				return err
-			}
-		} else {
-			return err
-		}
+	deployment, err := dbDeploy(instance)
+	if err != nil {
+		return err
+	}
+	if err := r.applyAndUpdateStatus(deployment, instance, "error create Deployment"); err != nil {
+		return err
 	}
 	logger.Info("Deployment Created")
 	// [TODO] Check Status
@@ -241,7 +278,18 @@ func updateTupDBStatus(instance *tmaxv1.TupDB, service *corev1.Service) error {
 
 func (r *ReconcileTupDB) createAndUpdateStatus(obj interface{}, instance *tmaxv1.TupDB, msg string) error {
 	if err := utils.CheckAndCreateObject(obj, instance, r.client, r.scheme, false); err != nil {
-		if err := r.updateErrorStatus(instance, tmaxv1.ConditionKeyProjectReady, corev1.ConditionFalse, msg, err.Error()); err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, msg, err.Error()); err != nil {
+			return err
+		}
+		return err
+	}
+	return nil
+}
+
+// Create the object, or update it if it is changed by TupDB spec
+func (r *ReconcileTupDB) applyAndUpdateStatus(obj interface{}, instance *tmaxv1.TupDB, msg string) error {
+	if err := utils.CheckAndUpdateObject(obj, instance, r.client, r.scheme); err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, msg, err.Error()); err != nil {
 			return err
 		}
 		return err
@@ -250,6 +298,7 @@ func (r *ReconcileTupDB) createAndUpdateStatus(obj interface{}, instance *tmaxv1
 }
 
 func (r *ReconcileTupDB) updateErrorStatus(instance *tmaxv1.TupDB, key status.ConditionType, stat corev1.ConditionStatus, reason, message string) error {
+	r.recorder.Event(instance, corev1.EventTypeWarning, tmaxv1.EventReasonReconcileFailed, fmt.Sprintf("%s: %s", reason, message))
 	if err := r.setCondition(instance, key, stat, reason, message); err != nil {
 		return err
 	}
@@ -0,0 +1,44 @@
+package tupdb
+
+import (
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Record events for the PipelineRun, if the TaskRun of the pipeline task is started/finished after the last reconciliation
+// PipelineRuns of TupDB have a single task and a fixed name, so TaskRuns (kept in the status progress) are compared instead
+func (r *ReconcileTupDB) recordPipelineRunEvents(instance *tmaxv1.TupDB, prevProgress []tmaxv1.TaskProgress, taskName string, pr *tektonv1.PipelineRun) {
+	if pr == nil {
+		return
+	}
+	prev := findTaskProgress(prevProgress, taskName)
+	cur := findTaskProgress(instance.Status.Progress, taskName)
+	if cur == nil || cur.TaskRunName == "" {
+		return
+	}
+	restarted := prev == nil || prev.TaskRunName != cur.TaskRunName
+
+	if restarted {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonPipelineRunStarted, "%s PipelineRun %s is started", taskName, pr.Name)
+	}
+
+	if cur.CompletionTime == nil || (!restarted && prev.CompletionTime != nil) {
+		return
+	}
+	if cur.State == string(tektonv1.TaskRunReasonSuccessful) {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonPipelineRunSucceeded, "%s PipelineRun %s is succeeded", taskName, pr.Name)
+	} else {
+		r.recorder.Eventf(instance, corev1.EventTypeWarning, tmaxv1.EventReasonPipelineRunFailed, "%s PipelineRun %s is failed (%s): %s", taskName, pr.Name, cur.State, cur.Message)
+	}
+}
+
+func findTaskProgress(progress []tmaxv1.TaskProgress, taskName string) *tmaxv1.TaskProgress {
+	for i := range progress {
+		if progress[i].Name == taskName {
+			return &progress[i]
+		}
+	}
+	return nil
+}
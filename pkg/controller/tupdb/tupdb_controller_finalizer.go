@@ -0,0 +1,51 @@
+package tupdb
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Add the finalizer, so the target DB resources are cleaned up before the TupDB is deleted
+func (r *ReconcileTupDB) addFinalizer(instance *tmaxv1.TupDB) error {
+	if utils.ContainsString(instance.Finalizers, tmaxv1.Finalizer) {
+		return nil
+	}
+	instance.Finalizers = append(instance.Finalizers, tmaxv1.Finalizer)
+
+	// Keep the status, as update overwrites the instance with the one from the api server
+	st := instance.Status.DeepCopy()
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		return err
+	}
+	instance.Status = *st
+	return nil
+}
+
+// Delete the target DB resources labelled with dbLabels (e.g., the deploy secret, which does not have the TupDB as an owner),
+// and then remove the finalizer
+func (r *ReconcileTupDB) finalize(instance *tmaxv1.TupDB) error {
+	if !utils.ContainsString(instance.Finalizers, tmaxv1.Finalizer) {
+		return nil
+	}
+
+	if err := utils.DeleteObjectsWithLabels(r.client, instance.Namespace, dbLabels(instance),
+		&appsv1.DeploymentList{},
+		&corev1.ServiceList{},
+		&corev1.SecretList{},
+		&corev1.PersistentVolumeClaimList{},
+	); err != nil {
+		log.Error(err, "cannot clean up DB resources", "Namespace", instance.Namespace, "Name", instance.Name)
+		if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, "error cleaning up resources", err.Error()); err != nil {
+			return err
+		}
+		return err
+	}
+
+	instance.Finalizers = utils.RemoveString(instance.Finalizers, tmaxv1.Finalizer)
+	return r.client.Update(context.TODO(), instance)
+}
@@ -0,0 +1,123 @@
+package tupdb
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Copy of the TupDB, whose passwords are read from the referred Secrets
+// Inline passwords (of the objects not migrated yet) are used as they are
+func withPasswords(c client.Client, tupDB *tmaxv1.TupDB) (*tmaxv1.TupDB, error) {
+	instance := tupDB.DeepCopy()
+
+	var err error
+	if instance.Spec.From.PasswordSecretRef != nil {
+		instance.Spec.From.Password, err = secretValue(c, instance.Namespace, instance.Spec.From.PasswordSecretRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if instance.Spec.To.PasswordSecretRef != nil {
+		instance.Spec.To.Password, err = secretValue(c, instance.Namespace, instance.Spec.To.PasswordSecretRef)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return instance, nil
+}
+
+func secretValue(c client.Client, namespace string, ref *corev1.SecretKeySelector) (string, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return "", err
+	}
+	value, exist := secret.Data[ref.Key]
+	if !exist {
+		return "", fmt.Errorf("there is no key %s in secret %s/%s", ref.Key, namespace, ref.Name)
+	}
+	return string(value), nil
+}
+
+// Move the inline passwords of the TupDB (given by v1 clients) to the Secrets owned by it, and refer to them instead
+func (r *ReconcileTupDB) migratePasswords(instance *tmaxv1.TupDB) error {
+	migrated := false
+	if instance.Spec.From.Password != "" && instance.Spec.From.PasswordSecretRef == nil {
+		ref, err := r.createPasswordSecret(instance, instance.GenSourcePasswordSecretName(), instance.Spec.From.Password)
+		if err != nil {
+			return err
+		}
+		instance.Spec.From.Password = ""
+		instance.Spec.From.PasswordSecretRef = ref
+		migrated = true
+	}
+	if instance.Spec.To.Password != "" && instance.Spec.To.PasswordSecretRef == nil {
+		ref, err := r.createPasswordSecret(instance, instance.GenTargetPasswordSecretName(), instance.Spec.To.Password)
+		if err != nil {
+			return err
+		}
+		instance.Spec.To.Password = ""
+		instance.Spec.To.PasswordSecretRef = ref
+		migrated = true
+	}
+	if !migrated {
+		return nil
+	}
+
+	// Keep the status, as update overwrites the instance with the one from the api server
+	st := instance.Status.DeepCopy()
+	if err := r.client.Update(context.TODO(), instance); err != nil {
+		return err
+	}
+	instance.Status = *st
+	return nil
+}
+
+// Create (or update) the Secret for the password - Secrets not owned by the TupDB are never overwritten
+func (r *ReconcileTupDB) createPasswordSecret(instance *tmaxv1.TupDB, name, password string) (*corev1.SecretKeySelector, error) {
+	ref := &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: name},
+		Key:                  tmaxv1.DBPasswordSecretKey,
+	}
+
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: instance.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	if err == nil {
+		if !metav1.IsControlledBy(secret, instance) {
+			err := fmt.Errorf("secret %s already exists, and is not owned by the TupDB", name)
+			if err := r.updateErrorStatus(instance, tmaxv1.DBConditionKeyDBReady, corev1.ConditionFalse, "error moving password to Secret", err.Error()); err != nil {
+				return nil, err
+			}
+			return nil, err
+		}
+		secret.Data = map[string][]byte{tmaxv1.DBPasswordSecretKey: []byte(password)}
+		return ref, r.client.Update(context.TODO(), secret)
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: instance.Namespace,
+			Labels:    instance.GenLabels(),
+		},
+		StringData: map[string]string{
+			tmaxv1.DBPasswordSecretKey: password,
+		},
+	}
+	if err := controllerutil.SetControllerReference(instance, secret, r.scheme); err != nil {
+		return nil, err
+	}
+	return ref, r.client.Create(context.TODO(), secret)
+}
@@ -0,0 +1,45 @@
+package tupdb
+
+import (
+	"context"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+func (r *ReconcileTupDB) watchPipelineRun(instance *tmaxv1.TupDB) error {
+	analyzePr, err := r.getPipelineRun(instance, instance.GenAnalyzePipelineName())
+	if err != nil {
+		return err
+	}
+	migratePr, err := r.getPipelineRun(instance, instance.GenMigratePipelineName())
+	if err != nil {
+		return err
+	}
+
+	// Progress of each pipeline task
+	prevProgress := instance.Status.Progress
+	instance.Status.Progress = append(
+		tmaxv1.GenTaskProgress([]string{tmaxv1.DBPipelineTaskNameAnalyzeDB}, analyzePr),
+		tmaxv1.GenTaskProgress([]string{tmaxv1.DBPipelineTaskNameMigrateDB}, migratePr)...,
+	)
+	r.recordPipelineRunEvents(instance, prevProgress, tmaxv1.DBPipelineTaskNameAnalyzeDB, analyzePr)
+	r.recordPipelineRunEvents(instance, prevProgress, tmaxv1.DBPipelineTaskNameMigrateDB, migratePr)
+
+	return nil
+}
+
+// Get PipelineRun - returns nil if not found
+func (r *ReconcileTupDB) getPipelineRun(instance *tmaxv1.TupDB, name string) (*tektonv1.PipelineRun, error) {
+	pr := &tektonv1.PipelineRun{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: instance.Namespace}, pr); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return pr, nil
+}
@@ -15,8 +15,8 @@ import (
 	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
 )
 
-// ConfigMap for WAS deployment spec
-func wasDeployConfigMap(tupWas *tmaxv1.TupWAS) (*corev1.ConfigMap, error) {
+// ConfigMap for WAS deployment spec of the track
+func wasDeployConfigMap(tupWas *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile, track string) (*corev1.ConfigMap, error) {
 	serializer := json.NewSerializerWithOptions(json.DefaultMetaFactory, nil, nil, json.SerializerOptions{
 		Yaml:   true,
 		Pretty: true,
@@ -24,7 +24,7 @@ func wasDeployConfigMap(tupWas *tmaxv1.TupWAS) (*corev1.ConfigMap, error) {
 	})
 
 	// Deployment object
-	deploy, err := wasDeploy(tupWas)
+	deploy, err := wasDeploy(tupWas, profile, track)
 	if err != nil {
 		return nil, err
 	}
@@ -35,7 +35,7 @@ func wasDeployConfigMap(tupWas *tmaxv1.TupWAS) (*corev1.ConfigMap, error) {
 
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tupWas.GenWasResourceName(),
+			Name:      tupWas.GenWasDeployConfigName(track),
 			Namespace: tupWas.Namespace,
 			Labels:    tupWas.GenLabels(),
 		},
@@ -46,6 +46,7 @@ func wasDeployConfigMap(tupWas *tmaxv1.TupWAS) (*corev1.ConfigMap, error) {
 }
 
 func wasDeployServiceAccount(tupWas *tmaxv1.TupWAS) *corev1.ServiceAccount {
+	// Git secret is attached/detached by deployGitSecret, as the secrets are also added by the token controller
 	return &corev1.ServiceAccount{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      tupWas.GenResourceName(),
@@ -111,17 +112,25 @@ func analyzePipeline(tupWas *tmaxv1.TupWAS) *tektonv1.Pipeline {
 				{Name: tmaxv1.WasPipelineParamNameGitUrl},
 				{
 					Name:    tmaxv1.WasPipelineParamNameGitRev,
-					Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "master"},
+					Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tmaxv1.WasGitDefaultRevision},
 				},
 				{Name: tmaxv1.WasPipelineParamNameSourceType},
 				{Name: tmaxv1.WasPipelineParamNameTargetType},
+				{
+					Name:    tmaxv1.WasPipelineParamNameIgnorePattern,
+					Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: `\.class$`},
+				},
 			},
-			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{{Name: tmaxv1.WasPipelineWorkspaceName}},
+			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{{Name: tmaxv1.WasPipelineWorkspaceName}, {Name: tmaxv1.WasPipelineWorkspaceNameSslCa}},
 			Tasks: []tektonv1.PipelineTask{{
 				Name:    string(tmaxv1.WasPipelineTaskNameClone),
 				TaskRef: &tektonv1.TaskRef{Name: tmaxv1.TaskNameGitClone, Kind: tektonv1.ClusterTaskKind},
+				// Clone afresh for every analysis, so that the changed source (url/revision) or the pushed commits are fetched
 				Params: []tektonv1.Param{{
 					Name:  "skipIfExists",
+					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "false"},
+				}, {
+					Name:  "deleteExisting",
 					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "true"},
 				}, {
 					Name:  "url",
@@ -137,6 +146,9 @@ func analyzePipeline(tupWas *tmaxv1.TupWAS) *tektonv1.Pipeline {
 					Name:      "output",
 					Workspace: tmaxv1.WasPipelineWorkspaceName,
 					SubPath:   "project",
+				}, {
+					Name:      "ssl-ca",
+					Workspace: tmaxv1.WasPipelineWorkspaceNameSslCa,
 				}},
 			}, {
 				Name:     string(tmaxv1.WasPipelineTaskNameAnalyze),
@@ -151,6 +163,13 @@ func analyzePipeline(tupWas *tmaxv1.TupWAS) *tektonv1.Pipeline {
 				}, {
 					Name:  "target-type",
 					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameTargetType)},
+				}, {
+					Name:  "ignore-pattern",
+					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameIgnorePattern)},
+				}, {
+					// Pipeline is updated if the image of the operator config is changed
+					Name:  "summary-image",
+					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: internal.SummaryImage},
 				}},
 				Workspaces: []tektonv1.WorkspacePipelineTaskBinding{{
 					Name:      "source",
@@ -166,11 +185,7 @@ func analyzePipeline(tupWas *tmaxv1.TupWAS) *tektonv1.Pipeline {
 	}
 }
 
-func buildDeployPipeline(tupWas *tmaxv1.TupWAS) (*tektonv1.Pipeline, error) {
-	builderImg, err := tupWas.GenBuilderImage()
-	if err != nil {
-		return nil, err
-	}
+func buildDeployPipeline(tupWas *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile) (*tektonv1.Pipeline, error) {
 	return &tektonv1.Pipeline{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      tupWas.GenBuildDeployPipelineName(),
@@ -181,6 +196,7 @@ func buildDeployPipeline(tupWas *tmaxv1.TupWAS) (*tektonv1.Pipeline, error) {
 			Params: []tektonv1.ParamSpec{
 				{Name: tmaxv1.WasPipelineParamNameAppName},
 				{Name: tmaxv1.WasPipelineParamNameDeployCfg, Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: ""}},
+				{Name: tmaxv1.WasPipelineParamNameDeployEnv, Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "{}"}},
 			},
 			Workspaces: []tektonv1.PipelineWorkspaceDeclaration{{Name: tmaxv1.WasPipelineWorkspaceName}},
 			Tasks: []tektonv1.PipelineTask{{
@@ -188,7 +204,7 @@ func buildDeployPipeline(tupWas *tmaxv1.TupWAS) (*tektonv1.Pipeline, error) {
 				TaskRef: &tektonv1.TaskRef{Name: tmaxv1.TaskNameBuild, Kind: tektonv1.ClusterTaskKind},
 				Params: []tektonv1.Param{{
 					Name:  "BUILDER_IMAGE",
-					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: builderImg},
+					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: profile.Spec.BuilderImage},
 				}, {
 					Name:  "IMAGE_URL",
 					Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.Spec.To.Image.Url},
@@ -205,22 +221,70 @@ func buildDeployPipeline(tupWas *tmaxv1.TupWAS) (*tektonv1.Pipeline, error) {
 					{Name: "app-name", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameAppName)}},
 					{Name: "image-url", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(tasks.%s.results.image-url)", tmaxv1.WasPipelineTaskNameBuild)}},
 					{Name: "deploy-cfg-name", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameDeployCfg)}},
-					{Name: "deploy-env-json", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "{}"}},
+					{Name: "deploy-env-json", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameDeployEnv)}},
 				},
 			}},
 		},
 	}, nil
 }
 
-func AnalyzePipelineRun(tupWas *tmaxv1.TupWAS) *tektonv1.PipelineRun {
-	return &tektonv1.PipelineRun{
+// Pipeline to deploy an image built before, without building it again
+func deployPipeline(tupWas *tmaxv1.TupWAS) *tektonv1.Pipeline {
+	return &tektonv1.Pipeline{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tupWas.GenAnalyzePipelineName(),
+			Name:      tupWas.GenDeployPipelineName(),
 			Namespace: tupWas.Namespace,
 			Labels:    tupWas.GenLabels(),
 		},
+		Spec: tektonv1.PipelineSpec{
+			Params: []tektonv1.ParamSpec{
+				{Name: tmaxv1.WasPipelineParamNameAppName},
+				{Name: tmaxv1.WasPipelineParamNameImageUrl},
+				{Name: tmaxv1.WasPipelineParamNameDeployCfg, Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: ""}},
+				{Name: tmaxv1.WasPipelineParamNameDeployEnv, Default: &tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: "{}"}},
+			},
+			Tasks: []tektonv1.PipelineTask{{
+				Name:    string(tmaxv1.WasPipelineTaskNameDeploy),
+				TaskRef: &tektonv1.TaskRef{Name: tmaxv1.TaskNameDeploy, Kind: tektonv1.ClusterTaskKind},
+				Params: []tektonv1.Param{
+					{Name: "app-name", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameAppName)}},
+					{Name: "image-url", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameImageUrl)}},
+					{Name: "deploy-cfg-name", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameDeployCfg)}},
+					{Name: "deploy-env-json", Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: fmt.Sprintf("$(params.%s)", tmaxv1.WasPipelineParamNameDeployEnv)}},
+				},
+			}},
+		},
+	}
+}
+
+func AnalyzePipelineRun(tupWas *tmaxv1.TupWAS) (*tektonv1.PipelineRun, error) {
+	analyzeParam, err := tupWas.GenAnalyzeParam()
+	if err != nil {
+		return nil, err
+	}
+
+	// CA bundle for git is in the git secret, if exists - only the CA key is projected, not the credential
+	sslCaWorkspace := tektonv1.WorkspaceBinding{Name: tmaxv1.WasPipelineWorkspaceNameSslCa, EmptyDir: &corev1.EmptyDirVolumeSource{}}
+	if tupWas.Spec.From.Git.SecretRef != nil {
+		optional := true
+		sslCaWorkspace.EmptyDir = nil
+		sslCaWorkspace.Secret = &corev1.SecretVolumeSource{
+			SecretName: tupWas.GenGitSecretName(),
+			Items:      []corev1.KeyToPath{{Key: tmaxv1.WasGitSecretKeyCa, Path: tmaxv1.WasGitSecretKeyCa}},
+			Optional:   &optional,
+		}
+	}
+
+	return &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: tupWas.GenAnalyzePipelineName() + "-",
+			Namespace:    tupWas.Namespace,
+			Labels:       tupWas.GenPipelineRunLabels(tmaxv1.WasPipelineTypeAnalyze),
+			Annotations:  tupWas.GenPipelineRunAnnotations(),
+		},
 		Spec: tektonv1.PipelineRunSpec{
-			PipelineRef: &tektonv1.PipelineRef{Name: tupWas.GenAnalyzePipelineName()},
+			PipelineRef:        &tektonv1.PipelineRef{Name: tupWas.GenAnalyzePipelineName()},
+			ServiceAccountName: tupWas.GenResourceName(),
 			Params: []tektonv1.Param{{
 				Name:  tmaxv1.WasPipelineParamNameProjectId,
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.Name},
@@ -232,35 +296,45 @@ func AnalyzePipelineRun(tupWas *tmaxv1.TupWAS) *tektonv1.PipelineRun {
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.Spec.From.Git.Revision},
 			}, {
 				Name:  tmaxv1.WasPipelineParamNameSourceType,
-				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.Spec.From.Type},
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: analyzeParam.Source},
 			}, {
 				Name:  tmaxv1.WasPipelineParamNameTargetType,
 				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.Spec.To.Type},
+			}, {
+				Name:  tmaxv1.WasPipelineParamNameIgnorePattern,
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: analyzeParam.IgnorePattern},
 			}},
 			Workspaces: []tektonv1.WorkspaceBinding{{
 				Name:                  tmaxv1.WasPipelineWorkspaceName,
 				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: tupWas.GenResourceName()},
-			}},
+			}, sslCaWorkspace},
 		},
-	}
+	}, nil
 }
 
 func BuildDeployPipelineRun(tupWas *tmaxv1.TupWAS) *tektonv1.PipelineRun {
+	// Deployment to be deployed depends on the rollout strategy
+	track := tupWas.GenRolloutTrack()
+
 	return &tektonv1.PipelineRun{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      tupWas.GenBuildDeployPipelineName(),
-			Namespace: tupWas.Namespace,
-			Labels:    tupWas.GenLabels(),
+			GenerateName: tupWas.GenBuildDeployPipelineName() + "-",
+			Namespace:    tupWas.Namespace,
+			Labels:       tupWas.GenPipelineRunLabels(tmaxv1.WasPipelineTypeBuildDeploy),
+			Annotations:  tupWas.GenPipelineRunAnnotations(),
 		},
 		Spec: tektonv1.PipelineRunSpec{
 			PipelineRef:        &tektonv1.PipelineRef{Name: tupWas.GenBuildDeployPipelineName()},
 			ServiceAccountName: tupWas.GenResourceName(),
 			Params: []tektonv1.Param{{
 				Name:  tmaxv1.WasPipelineParamNameAppName,
-				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.Name},
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenWasDeployName(track)},
 			}, {
 				Name:  tmaxv1.WasPipelineParamNameDeployCfg,
-				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenWasResourceName()},
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenWasDeployConfigName(track)},
+			}, {
+				Name:  tmaxv1.WasPipelineParamNameDeployEnv,
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenDeployEnvJson()},
 			}},
 			Workspaces: []tektonv1.WorkspaceBinding{{
 				Name:                  tmaxv1.WasPipelineWorkspaceName,
@@ -270,3 +344,35 @@ func BuildDeployPipelineRun(tupWas *tmaxv1.TupWAS) *tektonv1.PipelineRun {
 		},
 	}
 }
+
+// PipelineRun deploying the image built before, which is listed with Build/Deploy PipelineRuns
+func DeployPipelineRun(tupWas *tmaxv1.TupWAS, image string) *tektonv1.PipelineRun {
+	// Deployment to be deployed depends on the rollout strategy
+	track := tupWas.GenRolloutTrack()
+
+	return &tektonv1.PipelineRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: tupWas.GenDeployPipelineName() + "-",
+			Namespace:    tupWas.Namespace,
+			Labels:       tupWas.GenPipelineRunLabels(tmaxv1.WasPipelineTypeBuildDeploy),
+			Annotations:  tupWas.GenPipelineRunAnnotations(),
+		},
+		Spec: tektonv1.PipelineRunSpec{
+			PipelineRef:        &tektonv1.PipelineRef{Name: tupWas.GenDeployPipelineName()},
+			ServiceAccountName: tupWas.GenResourceName(),
+			Params: []tektonv1.Param{{
+				Name:  tmaxv1.WasPipelineParamNameAppName,
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenWasDeployName(track)},
+			}, {
+				Name:  tmaxv1.WasPipelineParamNameImageUrl,
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: image},
+			}, {
+				Name:  tmaxv1.WasPipelineParamNameDeployCfg,
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenWasDeployConfigName(track)},
+			}, {
+				Name:  tmaxv1.WasPipelineParamNameDeployEnv,
+				Value: tektonv1.ArrayOrString{Type: tektonv1.ParamTypeString, StringVal: tupWas.GenDeployEnvJson()},
+			}},
+		},
+	}
+}
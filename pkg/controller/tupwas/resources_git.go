@@ -0,0 +1,76 @@
+package tupwas
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Secret for git credential - copied from user's secret, annotated for Tekton
+func gitSecret(tupWas *tmaxv1.TupWAS, userSecret *corev1.Secret) (*corev1.Secret, error) {
+	if userSecret.Type != corev1.SecretTypeBasicAuth && userSecret.Type != corev1.SecretTypeSSHAuth {
+		return nil, fmt.Errorf("git secret %s should be type of %s or %s", userSecret.Name, corev1.SecretTypeBasicAuth, corev1.SecretTypeSSHAuth)
+	}
+
+	host, err := gitCredentialHost(tupWas.Spec.From.Git.Url, userSecret.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{}
+	for k, v := range userSecret.Data {
+		data[k] = v
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tupWas.GenGitSecretName(),
+			Namespace: tupWas.Namespace,
+			Labels:    tupWas.GenLabels(),
+			Annotations: map[string]string{
+				tmaxv1.WasGitSecretAnnotation0: host,
+			},
+		},
+		Type: userSecret.Type,
+		Data: data,
+	}, nil
+}
+
+// Host, to which Tekton applies the git credential
+// https://github.com/org/repo -> https://github.com (basic-auth)
+// git@github.com:org/repo, ssh://git@github.com:22/org/repo -> github.com, github.com:22 (ssh-auth)
+func gitCredentialHost(gitUrl string, secretType corev1.SecretType) (string, error) {
+	if strings.Contains(gitUrl, "://") {
+		u, err := url.Parse(gitUrl)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case secretType == corev1.SecretTypeBasicAuth && (u.Scheme == "http" || u.Scheme == "https"):
+			return fmt.Sprintf("%s://%s", u.Scheme, u.Host), nil
+		case secretType == corev1.SecretTypeSSHAuth && u.Scheme == "ssh":
+			return u.Host, nil
+		default:
+			return "", fmt.Errorf("git url %s cannot be used with secret type %s", gitUrl, secretType)
+		}
+	}
+
+	// scp-like syntax, i.e., [user@]host:path
+	if secretType != corev1.SecretTypeSSHAuth {
+		return "", fmt.Errorf("git url %s cannot be used with secret type %s", gitUrl, secretType)
+	}
+	hostPath := strings.SplitN(gitUrl, ":", 2)
+	if len(hostPath) != 2 {
+		return "", fmt.Errorf("git url %s is not valid", gitUrl)
+	}
+	host := hostPath[0]
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	return host, nil
+}
@@ -1,7 +1,10 @@
 package tupwas
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	corev1 "k8s.io/api/core/v1"
@@ -11,16 +14,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
-func wasService(tupWas *tmaxv1.TupWAS) (*corev1.Service, error) {
+func wasService(tupWas *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile) (*corev1.Service, error) {
 	serviceType := tupWas.Spec.To.ServiceType
 	// If service type is Ingress(=default), set it ClusterIP
 	if serviceType == "" || serviceType == tmaxv1.WasServiceTypeIngress {
 		serviceType = tmaxv1.WasServiceTypeClusterIP
 	}
-	port, err := tupWas.GenWasPort()
-	if err != nil {
-		return nil, err
-	}
 	return &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      tupWas.GenWasResourceName(),
@@ -31,19 +30,15 @@ func wasService(tupWas *tmaxv1.TupWAS) (*corev1.Service, error) {
 			Type: corev1.ServiceType(serviceType),
 			Ports: []corev1.ServicePort{
 				{
-					Port: port,
+					Port: profile.Spec.Port,
 				},
 			},
-			Selector: tupWas.GenWasServiceLabels(),
+			Selector: tupWas.GenWasServiceSelector(),
 		},
 	}, nil
 }
 
-func wasIngress(tupWas *tmaxv1.TupWAS) (*networkingv1beta1.Ingress, error) {
-	port, err := tupWas.GenWasPort()
-	if err != nil {
-		return nil, err
-	}
+func wasIngress(tupWas *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile) (*networkingv1beta1.Ingress, error) {
 	return &networkingv1beta1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        tupWas.GenWasResourceName(),
@@ -61,7 +56,7 @@ func wasIngress(tupWas *tmaxv1.TupWAS) (*networkingv1beta1.Ingress, error) {
 								ServiceName: tupWas.GenWasResourceName(),
 								ServicePort: intstr.IntOrString{
 									Type:   intstr.Int,
-									IntVal: port,
+									IntVal: profile.Spec.Port,
 								},
 							},
 						}},
@@ -72,10 +67,19 @@ func wasIngress(tupWas *tmaxv1.TupWAS) (*networkingv1beta1.Ingress, error) {
 	}, nil
 }
 
-func wasDeploy(tupWas *tmaxv1.TupWAS) (*appsv1.Deployment, error) {
-	port, err := tupWas.GenWasPort()
-	if err != nil {
-		return nil, err
+// WAS deployment of the track, the stable one (empty track) or the one rolled out by the rollout strategy
+func wasDeploy(tupWas *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile, track string) (*appsv1.Deployment, error) {
+	// Pods of the tracks are distinguished by the track label
+	// Canary pods are also selected by the selector of the stable deployment, but they are not adopted as they have the owner
+	podLabels := tupWas.GenWasServiceLabels()
+	if track != "" {
+		podLabels[tmaxv1.WasLabelKeyTrack] = track
+	}
+	// Stable pods are also labelled with the track for the service, but not selected by it (selector is immutable)
+	templateLabels := tupWas.GenWasServiceLabels()
+	templateLabels[tmaxv1.WasLabelKeyTrack] = track
+	if track == "" {
+		templateLabels[tmaxv1.WasLabelKeyTrack] = tmaxv1.WasTrackStable
 	}
 
 	dep := &appsv1.Deployment{
@@ -84,16 +88,16 @@ func wasDeploy(tupWas *tmaxv1.TupWAS) (*appsv1.Deployment, error) {
 		},
 		Spec: appsv1.DeploymentSpec{
 			Selector: &metav1.LabelSelector{
-				MatchLabels: tupWas.GenWasServiceLabels(),
+				MatchLabels: podLabels,
 			},
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: tupWas.GenWasServiceLabels(),
+					Labels: templateLabels,
 				},
 				Spec: corev1.PodSpec{
 					Containers: []corev1.Container{{
 						Ports: []corev1.ContainerPort{{
-							ContainerPort: port,
+							ContainerPort: profile.Spec.Port,
 						}},
 					}},
 				},
@@ -101,9 +105,144 @@ func wasDeploy(tupWas *tmaxv1.TupWAS) (*appsv1.Deployment, error) {
 		},
 	}
 
+	to := tupWas.Spec.To
+	switch track {
+	case "":
+		// Replicas is managed by the autoscaler, if it is set
+		if to.Autoscaling == nil {
+			dep.Spec.Replicas = to.Replicas
+		}
+		if to.RolloutStrategy == tmaxv1.WasRolloutStrategyRecreate {
+			dep.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+		}
+	case tmaxv1.WasTrackCanary:
+		// Canary pods are available only after they stay ready for the duration, and then promoted
+		replicas := int32(tmaxv1.WasDefaultCanaryReplicas)
+		duration := int32(tmaxv1.WasDefaultCanaryDurationSeconds)
+		if to.Canary != nil && to.Canary.Replicas != nil {
+			replicas = *to.Canary.Replicas
+		}
+		if to.Canary != nil && to.Canary.DurationSeconds != nil {
+			duration = *to.Canary.DurationSeconds
+		}
+		deadline := tmaxv1.WasDefaultProgressDeadlineSeconds + duration
+		dep.Spec.Replicas = &replicas
+		dep.Spec.MinReadySeconds = duration
+		dep.Spec.ProgressDeadlineSeconds = &deadline
+	default:
+		// Replicas of blue/green deployments should be set explicitly, as the inactive one is scaled down to 0
+		replicas := int32(1)
+		if to.Replicas != nil {
+			replicas = *to.Replicas
+		}
+		if to.Autoscaling != nil {
+			replicas = tmaxv1.WasDefaultAutoscalingMinReplicas
+			if to.Autoscaling.MinReplicas != nil {
+				replicas = *to.Autoscaling.MinReplicas
+			}
+		}
+		dep.Spec.Replicas = &replicas
+	}
+
+	container := &dep.Spec.Template.Spec.Containers[0]
+	if to.Resources != nil {
+		container.Resources = *to.Resources
+	}
+
+	// Environment variables with plain values are passed by deploy-env-json param, including JVM options of the TupWAS
+	// Default JVM options of the profile are only used when the TupWAS does not specify ones
+	if profile.Spec.JvmOptions != "" && to.JvmOptions == "" {
+		container.Env = append(container.Env, corev1.EnvVar{Name: tmaxv1.WasEnvNameJvmOptions, Value: profile.Spec.JvmOptions})
+	}
+	for _, env := range to.Env {
+		if env.ValueFrom != nil {
+			container.Env = append(container.Env, env)
+		}
+	}
+	container.EnvFrom = to.EnvFrom
+
+	for _, v := range to.Volumes {
+		vol := corev1.Volume{Name: v.Name}
+		switch {
+		case v.ConfigMap != nil:
+			vol.ConfigMap = v.ConfigMap
+		case v.Secret != nil:
+			vol.Secret = v.Secret
+		case v.PersistentVolumeClaim != nil:
+			vol.PersistentVolumeClaim = v.PersistentVolumeClaim
+		case v.EmptyDir != nil:
+			vol.EmptyDir = v.EmptyDir
+		default:
+			return nil, fmt.Errorf("volume %s does not have any source", v.Name)
+		}
+		dep.Spec.Template.Spec.Volumes = append(dep.Spec.Template.Spec.Volumes, vol)
+		container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{Name: v.Name, MountPath: v.MountPath, SubPath: v.SubPath, ReadOnly: v.ReadOnly})
+	}
+
+	// Not to route traffic to the WAS before the application is deployed in it
+	container.ReadinessProbe = tupWas.GenHealthProbe(profile)
+	container.LivenessProbe = tupWas.GenLivenessProbe(profile)
+
+	dep.Spec.Template.Spec.NodeSelector = to.NodeSelector
+	dep.Spec.Template.Spec.Tolerations = to.Tolerations
+
+	if profile.Spec.RunAsUser != nil {
+		dep.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{RunAsUser: profile.Spec.RunAsUser}
+	}
+
 	if tupWas.Spec.To.Image.RegSecret != "" {
 		dep.Spec.Template.Spec.ImagePullSecrets = append(dep.Spec.Template.Spec.ImagePullSecrets, corev1.LocalObjectReference{Name: tupWas.Spec.To.Image.RegSecret})
 	}
 
 	return dep, nil
 }
+
+// HorizontalPodAutoscaler for the WAS deployment the service routes to
+func wasAutoscaler(tupWas *tmaxv1.TupWAS) *autoscalingv2beta2.HorizontalPodAutoscaler {
+	autoscaling := tupWas.Spec.To.Autoscaling
+
+	minReplicas := int32(tmaxv1.WasDefaultAutoscalingMinReplicas)
+	if autoscaling.MinReplicas != nil {
+		minReplicas = *autoscaling.MinReplicas
+	}
+	targetCpu := int32(tmaxv1.WasDefaultAutoscalingTargetCPUPercentage)
+	if autoscaling.TargetCPUUtilizationPercentage != nil {
+		targetCpu = *autoscaling.TargetCPUUtilizationPercentage
+	}
+
+	metrics := []autoscalingv2beta2.MetricSpec{utilizationMetric(corev1.ResourceCPU, targetCpu)}
+	if autoscaling.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, utilizationMetric(corev1.ResourceMemory, *autoscaling.TargetMemoryUtilizationPercentage))
+	}
+
+	return &autoscalingv2beta2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      tupWas.GenWasResourceName(),
+			Namespace: tupWas.Namespace,
+			Labels:    tupWas.GenWasLabels(),
+		},
+		Spec: autoscalingv2beta2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2beta2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       tupWas.GenWasActiveDeployName(),
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: autoscaling.MaxReplicas,
+			Metrics:     metrics,
+		},
+	}
+}
+
+func utilizationMetric(resource corev1.ResourceName, percentage int32) autoscalingv2beta2.MetricSpec {
+	return autoscalingv2beta2.MetricSpec{
+		Type: autoscalingv2beta2.ResourceMetricSourceType,
+		Resource: &autoscalingv2beta2.ResourceMetricSource{
+			Name: resource,
+			Target: autoscalingv2beta2.MetricTarget{
+				Type:               autoscalingv2beta2.UtilizationMetricType,
+				AverageUtilization: &percentage,
+			},
+		},
+	}
+}
@@ -4,16 +4,18 @@ import (
 	"context"
 	"fmt"
 	"github.com/operator-framework/operator-sdk/pkg/status"
+	"github.com/tmax-cloud/l2c-operator/internal"
 	"github.com/tmax-cloud/l2c-operator/internal/utils"
 
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -39,11 +41,15 @@ func Add(mgr manager.Manager) error {
 
 // newReconciler returns a new reconcile.Reconciler
 func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-	return &ReconcileTupWAS{client: mgr.GetClient(), scheme: mgr.GetScheme()}
+	return &ReconcileTupWAS{client: mgr.GetClient(), scheme: mgr.GetScheme(), recorder: mgr.GetEventRecorderFor("tupwas-controller")}
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	if _, supported := exposureGvks[internal.ExposureApi]; !supported {
+		return fmt.Errorf("exposure API %s is not supported", internal.ExposureApi)
+	}
+
 	// Create a new controller
 	c, err := controller.New("tupwas-controller", mgr, controller.Options{Reconciler: r})
 	if err != nil {
@@ -106,7 +112,14 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
-	err = c.Watch(&source.Kind{Type: &networkingv1beta1.Ingress{}}, &handler.EnqueueRequestForOwner{
+	err = c.Watch(&source.Kind{Type: exposureObject()}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &tmaxv1.TupWAS{},
+	})
+	if err != nil {
+		return err
+	}
+	err = c.Watch(&source.Kind{Type: &autoscalingv2beta2.HorizontalPodAutoscaler{}}, &handler.EnqueueRequestForOwner{
 		IsController: true,
 		OwnerType:    &tmaxv1.TupWAS{},
 	})
@@ -130,9 +143,29 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 
 	tupWasReconciler, isTupWasReconciler := r.(*ReconcileTupWAS)
 	if isTupWasReconciler {
-		log.Info("Set ingress watcher!")
-		err = c.Watch(&source.Kind{Type: &networkingv1beta1.Ingress{}}, &handler.EnqueueRequestsFromMapFunc{
-			ToRequests: handler.ToRequestsFunc(tupWasReconciler.ingressMapper),
+		log.Info("Set ingress/deployment watcher!")
+		err = c.Watch(&source.Kind{Type: exposureObject()}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(tupWasReconciler.wasMapper),
+		})
+		if err != nil {
+			return err
+		}
+		err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(tupWasReconciler.wasMapper),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = c.Watch(&source.Kind{Type: &tmaxv1.TupBuilderProfile{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(tupWasReconciler.builderProfileMapper),
+		})
+		if err != nil {
+			return err
+		}
+
+		err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(tupWasReconciler.gitSecretMapper),
 		})
 		if err != nil {
 			return err
@@ -149,8 +182,9 @@ var _ reconcile.Reconciler = &ReconcileTupWAS{}
 type ReconcileTupWAS struct {
 	// This client, initialized using mgr.Client() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
-	client client.Client
-	scheme *runtime.Scheme
+	client   client.Client
+	scheme   *runtime.Scheme
+	recorder record.EventRecorder
 }
 
 // Reconcile reads that state of the cluster for a TupWAS object and makes changes based on the state read
@@ -164,7 +198,7 @@ func (r *ReconcileTupWAS) Reconcile(request reconcile.Request) (reconcile.Result
 	if err := r.client.Get(context.TODO(), request.NamespacedName, instance); err != nil {
 		if errors.IsNotFound(err) {
 			// Request object not found, could have been deleted after reconcile request.
-			// Owned objects are automatically garbage collected. For additional cleanup logic use finalizers.
+			// Owned objects are automatically garbage collected, and the others are deleted by the finalizer.
 			// Return and don't requeue
 			return reconcile.Result{}, nil
 		}
@@ -172,6 +206,14 @@ func (r *ReconcileTupWAS) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, err
 	}
 
+	// Clean up the resources not owned by the TupWAS, before it is deleted
+	if instance.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.finalize(instance)
+	}
+	if err := r.addFinalizer(instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Set default Conditions
 	if len(instance.Status.Conditions) == 0 {
 		instance.Status.SetDefaults()
@@ -182,12 +224,16 @@ func (r *ReconcileTupWAS) Reconcile(request reconcile.Request) (reconcile.Result
 		return reconcile.Result{}, err
 	}
 
+	// Quality gate
+	r.checkQualityGate(instance)
+
 	// Resources
 	if err := r.deployResources(instance); err != nil {
 		return reconcile.Result{}, err
 	}
 
 	// Update status!
+	instance.Status.ObservedGeneration = instance.Generation
 	if err := r.client.Status().Update(context.TODO(), instance); err != nil {
 		return reconcile.Result{}, err
 	}
@@ -196,6 +242,7 @@ func (r *ReconcileTupWAS) Reconcile(request reconcile.Request) (reconcile.Result
 }
 
 func (r *ReconcileTupWAS) updateErrorStatus(instance *tmaxv1.TupWAS, key status.ConditionType, stat corev1.ConditionStatus, reason, message string) error {
+	r.recorder.Event(instance, corev1.EventTypeWarning, tmaxv1.EventReasonReconcileFailed, fmt.Sprintf("%s: %s", reason, message))
 	if err := r.setCondition(instance, key, stat, reason, message); err != nil {
 		return err
 	}
@@ -231,8 +278,19 @@ func (r *ReconcileTupWAS) createAndUpdateStatus(obj interface{}, instance *tmaxv
 	return nil
 }
 
-// To watch WAS ingress - does not have TupWAS as an owner
-func (r *ReconcileTupWAS) ingressMapper(ing handler.MapObject) []reconcile.Request {
+// Create the object, or update it if it is changed by TupWAS spec or operator config, or by others
+func (r *ReconcileTupWAS) applyAndUpdateStatus(obj interface{}, instance *tmaxv1.TupWAS, msg string, ignoredFields ...string) error {
+	if err := utils.CheckAndUpdateObject(obj, instance, r.client, r.scheme, ignoredFields...); err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, msg, err.Error()); err != nil {
+			return err
+		}
+		return err
+	}
+	return nil
+}
+
+// To watch WAS ingress/deployment - does not have TupWAS as an owner
+func (r *ReconcileTupWAS) wasMapper(ing handler.MapObject) []reconcile.Request {
 	label := ing.Meta.GetLabels()
 	setTupWas := ""
 	isTierWas := false
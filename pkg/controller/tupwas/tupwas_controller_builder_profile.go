@@ -0,0 +1,121 @@
+package tupwas
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/tmax-cloud/l2c-operator/internal"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Get TupBuilderProfile for the target WAS type - returns nil if there is no matching profile
+// If there are multiple profiles for the same target type, the one with the smallest name is used
+func (r *ReconcileTupWAS) getBuilderProfile(instance *tmaxv1.TupWAS) (*tmaxv1.TupBuilderProfile, error) {
+	profiles := &tmaxv1.TupBuilderProfileList{}
+	if err := r.client.List(context.TODO(), profiles); err != nil {
+		return nil, err
+	}
+
+	var profile *tmaxv1.TupBuilderProfile
+	for i, p := range profiles.Items {
+		if p.Spec.TargetType != instance.Spec.To.Type {
+			continue
+		}
+		if profile == nil || p.Name < profile.Name {
+			profile = &profiles.Items[i]
+		}
+	}
+
+	return profile, nil
+}
+
+// To watch TupBuilderProfile - requeue all TupWAS objects targeting the profile's target type
+func (r *ReconcileTupWAS) builderProfileMapper(obj handler.MapObject) []reconcile.Request {
+	profile, isProfile := obj.Object.(*tmaxv1.TupBuilderProfile)
+	if !isProfile {
+		return []reconcile.Request{}
+	}
+
+	tupWasList := &tmaxv1.TupWASList{}
+	if err := r.client.List(context.TODO(), tupWasList); err != nil {
+		log.Error(err, "cannot list TupWAS")
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, tupWas := range tupWasList.Items {
+		if tupWas.Spec.To.Type == profile.Spec.TargetType {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: tupWas.Name, Namespace: tupWas.Namespace},
+			})
+		}
+	}
+
+	return requests
+}
+
+// Seed or override the default TupBuilderProfiles (jeus-7, jeus-8) with the deprecated builder image flags
+// Profiles are created if they do not exist, or their builder images are updated to the flags
+func SeedBuilderProfiles(cfg *rest.Config, scheme *runtime.Scheme) error {
+	images := []struct {
+		name       string
+		targetType string
+		image      string
+	}{
+		{name: "jeus-7", targetType: "jeus:7", image: internal.BuilderImageJeus7},
+		{name: "jeus-8", targetType: "jeus:8", image: internal.BuilderImageJeus8},
+	}
+
+	var c client.Client
+	for _, i := range images {
+		if i.image == "" {
+			continue
+		}
+		if c == nil {
+			var err error
+			if c, err = client.New(cfg, client.Options{Scheme: scheme}); err != nil {
+				return err
+			}
+		}
+
+		profile := &tmaxv1.TupBuilderProfile{}
+		err := c.Get(context.TODO(), types.NamespacedName{Name: i.name}, profile)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if errors.IsNotFound(err) {
+			profile = &tmaxv1.TupBuilderProfile{
+				ObjectMeta: metav1.ObjectMeta{Name: i.name},
+				Spec: tmaxv1.TupBuilderProfileSpec{
+					TargetType:      i.targetType,
+					BuilderImage:    i.image,
+					Port:            8080,
+					HealthCheckPath: tmaxv1.WasDefaultHealthCheckPath,
+				},
+			}
+			if err := c.Create(context.TODO(), profile); err != nil {
+				return err
+			}
+			log.Info(fmt.Sprintf("Created TupBuilderProfile %s with the deprecated builder image flag", i.name))
+			continue
+		}
+		if profile.Spec.BuilderImage != i.image {
+			profile.Spec.BuilderImage = i.image
+			if err := c.Update(context.TODO(), profile); err != nil {
+				return err
+			}
+			log.Info(fmt.Sprintf("Updated builder image of TupBuilderProfile %s with the deprecated builder image flag", i.name))
+		}
+	}
+
+	return nil
+}
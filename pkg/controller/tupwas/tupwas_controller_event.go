@@ -0,0 +1,33 @@
+package tupwas
+
+import (
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Record events for the latest PipelineRun of the pipeline type, if it is started/finished after the last reconciliation
+// prevName/prevCompleted are the name/completion of the PipelineRun, kept in the status before watching it
+func (r *ReconcileTupWAS) recordPipelineRunEvents(instance *tmaxv1.TupWAS, pipelineType string, prevName string, prevCompleted bool, pr *tektonv1.PipelineRun) {
+	if pr == nil {
+		return
+	}
+
+	if pr.Name != prevName {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonPipelineRunStarted, "%s PipelineRun %s is started", pipelineType, pr.Name)
+	}
+
+	if pr.Status.CompletionTime == nil || (pr.Name == prevName && prevCompleted) {
+		return
+	}
+	reason, message := "", ""
+	if len(pr.Status.Conditions) != 0 {
+		reason, message = pr.Status.Conditions[0].Reason, pr.Status.Conditions[0].Message
+	}
+	if reason == string(tektonv1.PipelineRunReasonSuccessful) {
+		r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonPipelineRunSucceeded, "%s PipelineRun %s is succeeded", pipelineType, pr.Name)
+	} else {
+		r.recorder.Eventf(instance, corev1.EventTypeWarning, tmaxv1.EventReasonPipelineRunFailed, "%s PipelineRun %s is failed (%s): %s", pipelineType, pr.Name, reason, message)
+	}
+}
@@ -0,0 +1,328 @@
+package tupwas
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"github.com/tmax-cloud/l2c-operator/internal"
+)
+
+// WAS/IDE are exposed by networking.k8s.io/v1 or v1beta1 Ingresses, or OpenShift Routes, whichever is detected at startup
+// Exposures are handled in the form of v1beta1 Ingress, and converted to/from the objects of the API here
+
+const (
+	// Routes have certificates inline, so the name of the TLS secret is kept in the annotation
+	ExposureAnnotationTlsSecret = "tmax.io/tls-secret"
+)
+
+var exposureGvks = map[string]schema.GroupVersionKind{
+	internal.ExposureApiIngressV1:      {Group: "networking.k8s.io", Version: "v1", Kind: "Ingress"},
+	internal.ExposureApiIngressV1beta1: {Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"},
+	internal.ExposureApiRoute:          {Group: "route.openshift.io", Version: "v1", Kind: "Route"},
+}
+
+// Empty object of the exposure API, e.g., to watch or to get
+func exposureObject() *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(exposureGvks[internal.ExposureApi])
+	return obj
+}
+
+// A route has a single host, so an ingress having multiple rules is exposed by a route per rule
+func routeName(ing *networkingv1beta1.Ingress, i int) string {
+	if len(ing.Spec.Rules) == 1 {
+		return ing.Name
+	}
+	return fmt.Sprintf("%s-%d", ing.Name, i)
+}
+
+// Objects of the exposure API, converted from the ingress
+func (r *ReconcileTupWAS) exposureObjects(ing *networkingv1beta1.Ingress) ([]*unstructured.Unstructured, error) {
+	switch internal.ExposureApi {
+	case internal.ExposureApiIngressV1beta1:
+		content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(ing)
+		if err != nil {
+			return nil, err
+		}
+		obj := &unstructured.Unstructured{Object: content}
+		obj.SetGroupVersionKind(exposureGvks[internal.ExposureApi])
+		return []*unstructured.Unstructured{obj}, nil
+	case internal.ExposureApiIngressV1:
+		var rules []interface{}
+		for _, rule := range ing.Spec.Rules {
+			var paths []interface{}
+			if rule.HTTP != nil {
+				for _, p := range rule.HTTP.Paths {
+					path := p.Path
+					if path == "" {
+						path = "/"
+					}
+					paths = append(paths, map[string]interface{}{
+						"path":     path,
+						"pathType": "Prefix",
+						"backend": map[string]interface{}{
+							"service": map[string]interface{}{
+								"name": p.Backend.ServiceName,
+								"port": ingressV1ServicePort(p.Backend.ServicePort),
+							},
+						},
+					})
+				}
+			}
+			rules = append(rules, map[string]interface{}{
+				"host": rule.Host,
+				"http": map[string]interface{}{"paths": paths},
+			})
+		}
+		spec := map[string]interface{}{"rules": rules}
+		if len(ing.Spec.TLS) != 0 {
+			var tls []interface{}
+			for _, t := range ing.Spec.TLS {
+				var hosts []interface{}
+				for _, h := range t.Hosts {
+					hosts = append(hosts, h)
+				}
+				tls = append(tls, map[string]interface{}{"hosts": hosts, "secretName": t.SecretName})
+			}
+			spec["tls"] = tls
+		}
+
+		obj := newExposureObject(ing, ing.Name)
+		obj.Object["spec"] = spec
+		return []*unstructured.Unstructured{obj}, nil
+	case internal.ExposureApiRoute:
+		var objs []*unstructured.Unstructured
+		for i, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+				return nil, fmt.Errorf("rule %d of ingress %s does not have any backend", i, ing.Name)
+			}
+			backend := rule.HTTP.Paths[0].Backend
+			targetPort, err := r.routeTargetPort(ing.Namespace, &backend)
+			if err != nil {
+				return nil, err
+			}
+			spec := map[string]interface{}{
+				"to": map[string]interface{}{
+					"kind": "Service",
+					"name": backend.ServiceName,
+				},
+				"port": map[string]interface{}{"targetPort": targetPort},
+			}
+			// Host is left empty until it is generated, as routes cannot claim the same host (placeholder)
+			if rule.Host != "" && rule.Host != IngressDefaultHost {
+				spec["host"] = rule.Host
+			}
+
+			obj := newExposureObject(ing, routeName(ing, i))
+			if secretName := tlsSecretOf(ing, rule.Host); secretName != "" {
+				secret := &corev1.Secret{}
+				if err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: ing.Namespace}, secret); err != nil {
+					return nil, err
+				}
+				tls := map[string]interface{}{
+					"termination":                   "edge",
+					"insecureEdgeTerminationPolicy": "Redirect",
+					"certificate":                   string(secret.Data[corev1.TLSCertKey]),
+					"key":                           string(secret.Data[corev1.TLSPrivateKeyKey]),
+				}
+				if ca, exist := secret.Data[TlsSecretKeyCa]; exist {
+					tls["caCertificate"] = string(ca)
+				}
+				spec["tls"] = tls
+
+				annotations := obj.GetAnnotations()
+				annotations[ExposureAnnotationTlsSecret] = secretName
+				obj.SetAnnotations(annotations)
+			}
+			obj.Object["spec"] = spec
+			objs = append(objs, obj)
+		}
+		return objs, nil
+	}
+
+	return nil, fmt.Errorf("exposure API %s is not supported", internal.ExposureApi)
+}
+
+// Service port of networking.k8s.io/v1 ingress backend, by the name or the number
+func ingressV1ServicePort(port intstr.IntOrString) map[string]interface{} {
+	if port.Type == intstr.String {
+		return map[string]interface{}{"name": port.StrVal}
+	}
+	return map[string]interface{}{"number": int64(port.IntVal)}
+}
+
+// Target port of the route for the service port of the ingress backend
+// Routes refer to the name of the service port, or the port of the endpoints (target port of the service)
+func (r *ReconcileTupWAS) routeTargetPort(namespace string, backend *networkingv1beta1.IngressBackend) (interface{}, error) {
+	if backend.ServicePort.Type == intstr.String {
+		return backend.ServicePort.StrVal, nil
+	}
+
+	svc := &corev1.Service{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: backend.ServiceName, Namespace: namespace}, svc); err != nil {
+		// Service may not be in the cache yet
+		if errors.IsNotFound(err) {
+			return int64(backend.ServicePort.IntVal), nil
+		}
+		return nil, err
+	}
+	for _, port := range svc.Spec.Ports {
+		if port.Port != backend.ServicePort.IntVal {
+			continue
+		}
+		if port.Name != "" {
+			return port.Name, nil
+		}
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal != 0 {
+			return int64(port.TargetPort.IntVal), nil
+		}
+		break
+	}
+	return int64(backend.ServicePort.IntVal), nil
+}
+
+func newExposureObject(ing *networkingv1beta1.Ingress, name string) *unstructured.Unstructured {
+	annotations := map[string]string{}
+	for k, v := range ing.Annotations {
+		annotations[k] = v
+	}
+
+	obj := exposureObject()
+	obj.SetName(name)
+	obj.SetNamespace(ing.Namespace)
+	obj.SetLabels(ing.Labels)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func tlsSecretOf(ing *networkingv1beta1.Ingress, host string) string {
+	for _, t := range ing.Spec.TLS {
+		for _, h := range t.Hosts {
+			if h == host {
+				return t.SecretName
+			}
+		}
+	}
+	return ""
+}
+
+// Read hosts, TLS and the load balancer address of the exposure objects into the ingress (generated by resource functions)
+func (r *ReconcileTupWAS) getExposure(ing *networkingv1beta1.Ingress) error {
+	if internal.ExposureApi != internal.ExposureApiRoute {
+		obj := exposureObject()
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: ing.Name, Namespace: ing.Namespace}, obj); err != nil {
+			return err
+		}
+		// Fields read here have the same form in v1 and v1beta1
+		current := &networkingv1beta1.Ingress{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, current); err != nil {
+			return err
+		}
+		for i := range ing.Spec.Rules {
+			if i < len(current.Spec.Rules) {
+				ing.Spec.Rules[i].Host = current.Spec.Rules[i].Host
+			}
+		}
+		ing.Spec.TLS = current.Spec.TLS
+		ing.Status = current.Status
+		return nil
+	}
+
+	ing.Spec.TLS = nil
+	for i := range ing.Spec.Rules {
+		obj := exposureObject()
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: routeName(ing, i), Namespace: ing.Namespace}, obj); err != nil {
+			return err
+		}
+		host, _, err := unstructured.NestedString(obj.Object, "spec", "host")
+		if err != nil {
+			return err
+		}
+		// Route is created without a host, until it is generated
+		if host == "" {
+			host = IngressDefaultHost
+		}
+		ing.Spec.Rules[i].Host = host
+
+		// Certificate of the secret may be renewed after it is applied, then the route should be updated again
+		if secretName := obj.GetAnnotations()[ExposureAnnotationTlsSecret]; secretName != "" {
+			crt, _, err := unstructured.NestedString(obj.Object, "spec", "tls", "certificate")
+			if err != nil {
+				return err
+			}
+			secret := &corev1.Secret{}
+			if err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: ing.Namespace}, secret); err != nil && !errors.IsNotFound(err) {
+				return err
+			} else if err == nil && string(secret.Data[corev1.TLSCertKey]) == crt {
+				addTlsHost(ing, secretName, host)
+			}
+		}
+
+		// Address of the router
+		if i == 0 {
+			ing.Status.LoadBalancer.Ingress = nil
+			routerIngresses, _, err := unstructured.NestedSlice(obj.Object, "status", "ingress")
+			if err != nil {
+				return err
+			}
+			if len(routerIngresses) != 0 {
+				routerIngress, _ := routerIngresses[0].(map[string]interface{})
+				if hostname, _, _ := unstructured.NestedString(routerIngress, "routerCanonicalHostname"); hostname != "" {
+					ing.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: hostname}}
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func addTlsHost(ing *networkingv1beta1.Ingress, secretName, host string) {
+	for i := range ing.Spec.TLS {
+		if ing.Spec.TLS[i].SecretName == secretName {
+			ing.Spec.TLS[i].Hosts = append(ing.Spec.TLS[i].Hosts, host)
+			return
+		}
+	}
+	ing.Spec.TLS = append(ing.Spec.TLS, networkingv1beta1.IngressTLS{Hosts: []string{host}, SecretName: secretName})
+}
+
+// Apply hosts and TLS of the ingress to the exposure objects
+func (r *ReconcileTupWAS) updateExposure(ing *networkingv1beta1.Ingress) error {
+	objs, err := r.exposureObjects(ing)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		current := exposureObject()
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: obj.GetName(), Namespace: obj.GetNamespace()}, current); err != nil {
+			return err
+		}
+		current.Object["spec"] = obj.Object["spec"]
+		if internal.ExposureApi == internal.ExposureApiRoute {
+			annotations := current.GetAnnotations()
+			if annotations == nil {
+				annotations = map[string]string{}
+			}
+			if secretName, exist := obj.GetAnnotations()[ExposureAnnotationTlsSecret]; exist {
+				annotations[ExposureAnnotationTlsSecret] = secretName
+			} else {
+				delete(annotations, ExposureAnnotationTlsSecret)
+			}
+			current.SetAnnotations(annotations)
+		}
+		if err := r.client.Update(context.TODO(), current); err != nil {
+			return err
+		}
+	}
+	return nil
+}
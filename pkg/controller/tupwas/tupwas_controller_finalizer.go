@@ -0,0 +1,53 @@
+package tupwas
+
+import (
+	"context"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Add the finalizer, so the WAS resources are cleaned up before the TupWAS is deleted
+func (r *ReconcileTupWAS) addFinalizer(instance *tmaxv1.TupWAS) error {
+	if utils.ContainsString(instance.Finalizers, tmaxv1.Finalizer) {
+		return nil
+	}
+	instance.Finalizers = append(instance.Finalizers, tmaxv1.Finalizer)
+	return r.client.Update(context.TODO(), instance)
+}
+
+// Delete the WAS resources labelled with GenWasLabels, which do not have the TupWAS as an owner
+// (e.g., deployments applied by the deploy task, service and ingresses), and then remove the finalizer
+func (r *ReconcileTupWAS) finalize(instance *tmaxv1.TupWAS) error {
+	if !utils.ContainsString(instance.Finalizers, tmaxv1.Finalizer) {
+		return nil
+	}
+
+	exposures := &unstructured.UnstructuredList{}
+	exposures.SetGroupVersionKind(exposureObject().GroupVersionKind())
+	exposures.SetKind(exposures.GetKind() + "List")
+
+	if err := utils.DeleteObjectsWithLabels(r.client, instance.Namespace, instance.GenWasLabels(),
+		&appsv1.DeploymentList{},
+		&corev1.ServiceList{},
+		exposures,
+		&autoscalingv2beta2.HorizontalPodAutoscalerList{},
+	); err != nil {
+		log.Error(err, "cannot clean up WAS resources", "Namespace", instance.Namespace, "Name", instance.Name)
+		if len(instance.Status.Conditions) == 0 {
+			instance.Status.SetDefaults()
+		}
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error cleaning up resources", err.Error()); err != nil {
+			return err
+		}
+		return err
+	}
+
+	instance.Finalizers = utils.RemoveString(instance.Finalizers, tmaxv1.Finalizer)
+	return r.client.Update(context.TODO(), instance)
+}
@@ -0,0 +1,117 @@
+package tupwas
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Copy git credential to a Tekton-annotated secret and attach it to the ServiceAccount
+func (r *ReconcileTupWAS) deployGitSecret(instance *tmaxv1.TupWAS) error {
+	secretRef := instance.Spec.From.Git.SecretRef
+	if secretRef == nil {
+		return r.removeGitSecret(instance)
+	}
+
+	// Get user's secret
+	userSecret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretRef.Name, Namespace: instance.Namespace}, userSecret); err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting git secret", err.Error()); err != nil {
+			return err
+		}
+		return err
+	}
+
+	// Secret for Tekton
+	desiredSecret, err := gitSecret(instance, userSecret)
+	if err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "git secret is not valid", err.Error()); err != nil {
+			return err
+		}
+		return nil
+	}
+	// Keep it up-to-date with user's secret (recreated if the type is changed)
+	if err := r.applyAndUpdateStatus(desiredSecret, instance, "error getting/creating git secret"); err != nil {
+		return err
+	}
+
+	// Attach the secret to the ServiceAccount
+	sa := &corev1.ServiceAccount{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenResourceName(), Namespace: instance.Namespace}, sa); err != nil {
+		return err
+	}
+	for _, s := range sa.Secrets {
+		if s.Name == desiredSecret.Name {
+			return nil
+		}
+	}
+	sa.Secrets = append(sa.Secrets, corev1.ObjectReference{Name: desiredSecret.Name})
+	if err := r.client.Update(context.TODO(), sa); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Detach the git credential from the ServiceAccount and delete it, after spec.from.git.secretRef is removed
+func (r *ReconcileTupWAS) removeGitSecret(instance *tmaxv1.TupWAS) error {
+	sa := &corev1.ServiceAccount{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenResourceName(), Namespace: instance.Namespace}, sa); err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if err == nil {
+		var secrets []corev1.ObjectReference
+		for _, s := range sa.Secrets {
+			if s.Name != instance.GenGitSecretName() {
+				secrets = append(secrets, s)
+			}
+		}
+		if len(secrets) != len(sa.Secrets) {
+			sa.Secrets = secrets
+			if err := r.client.Update(context.TODO(), sa); err != nil {
+				return err
+			}
+		}
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenGitSecretName(), Namespace: instance.Namespace}, secret); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if err := r.client.Delete(context.TODO(), secret); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	log.Info("Deleted git secret, as spec.from.git.secretRef is removed", "Namespace", secret.Namespace, "Name", secret.Name)
+
+	return nil
+}
+
+// Map user's git secret to TupWAS referring to it, to keep the Tekton secret up-to-date
+func (r *ReconcileTupWAS) gitSecretMapper(obj handler.MapObject) []reconcile.Request {
+	tupWasList := &tmaxv1.TupWASList{}
+	if err := r.client.List(context.TODO(), tupWasList, client.InNamespace(obj.Meta.GetNamespace())); err != nil {
+		log.Error(err, "cannot list TupWAS")
+		return []reconcile.Request{}
+	}
+
+	var requests []reconcile.Request
+	for _, tupWas := range tupWasList.Items {
+		secretRef := tupWas.Spec.From.Git.SecretRef
+		if secretRef != nil && secretRef.Name == obj.Meta.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: tupWas.Name, Namespace: tupWas.Namespace},
+			})
+		}
+	}
+
+	return requests
+}
@@ -8,6 +8,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"strings"
 )
 
 const (
@@ -18,16 +19,16 @@ const (
 
 func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 	// Generate VSCode - Secret/Service/Ingress/Deployment
-	idePassword := []byte("")
+	passwordSecret := ""
 	ideUrl := ""
 	reportUrl := ""
 
-	// Generate Secret
+	// Generate Secret - only created, as the password is generated randomly
 	ideSecret := ideSecret(instance)
 	if err := r.createAndUpdateStatus(ideSecret, instance, "error getting/creating pipeline"); err != nil {
 		return err
 	}
-	// Check IDE Password
+	// Check IDE Password Secret
 	err := r.client.Get(context.TODO(), types.NamespacedName{Name: ideSecret.Name, Namespace: ideSecret.Namespace}, ideSecret)
 	if err != nil && !errors.IsNotFound(err) {
 		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating secret", err.Error()); err != nil {
@@ -36,7 +37,7 @@ func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 		return err
 	}
 	if err == nil {
-		idePassword = ideSecret.Data["password"]
+		passwordSecret = ideSecret.Name
 	}
 
 	// Generate Service
@@ -47,7 +48,7 @@ func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 		}
 		return err
 	}
-	if err := r.createAndUpdateStatus(ideService, instance, "error getting/creating pipeline"); err != nil {
+	if err := r.applyAndUpdateStatus(ideService, instance, "error getting/creating service"); err != nil {
 		return err
 	}
 
@@ -59,12 +60,22 @@ func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 		}
 		return err
 	}
-	if err := r.createAndUpdateStatus(ideIngress, instance, "error getting/creating pipeline"); err != nil {
+	exposures, err := r.exposureObjects(ideIngress)
+	if err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating ingress", err.Error()); err != nil {
+			return err
+		}
 		return err
 	}
+	// Hosts/TLS in the spec are set afterwards, by updateExposure
+	for _, exposure := range exposures {
+		if err := r.applyAndUpdateStatus(exposure, instance, "error getting/creating ingress", "spec"); err != nil {
+			return err
+		}
+	}
 
 	// Check ingress status first before deploy
-	err = r.client.Get(context.TODO(), types.NamespacedName{Name: ideIngress.Name, Namespace: ideIngress.Namespace}, ideIngress)
+	err = r.getExposure(ideIngress)
 	if err != nil && !errors.IsNotFound(err) {
 		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating ingress", err.Error()); err != nil {
 			return err
@@ -75,22 +86,48 @@ func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 			return err
 		}
 	} else if err == nil {
-		if len(ideIngress.Status.LoadBalancer.Ingress) == 0 {
-			if err := r.setCondition(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "ingress for ide is not ready", "ingress didn't get external ip yet"); err != nil {
-				return err
+		hostsChanged := false
+		if len(ideIngress.Spec.Rules) == 3 {
+			// Set hosts (after the load balancer is given, if the host template needs its address)
+			// They are generated every time, so that the changes of the host template are applied
+			var hosts []string
+			for _, prefix := range []string{IdePrefix, ReportPrefix, ConfigPrefix} {
+				host, err := ingressHost(instance, ideIngress, prefix)
+				if err != nil {
+					if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "invalid ingress host", err.Error()); err != nil {
+						return err
+					}
+					return nil
+				}
+				hosts = append(hosts, host)
 			}
-		} else if len(ideIngress.Status.LoadBalancer.Ingress) != 0 && len(ideIngress.Spec.Rules) == 3 && (ideIngress.Spec.Rules[0].Host == IngressDefaultHost || ideIngress.Spec.Rules[1].Host == IngressDefaultHost || ideIngress.Spec.Rules[2].Host == IngressDefaultHost) {
-			// If Loadbalancer is given to the ingress, but host is not set, set host!
-			ideIngress.Spec.Rules[0].Host = fmt.Sprintf("%s.%s.%s.%s.nip.io", IdePrefix, instance.Name, instance.Namespace, ideIngress.Status.LoadBalancer.Ingress[0].IP)
-			ideIngress.Spec.Rules[1].Host = fmt.Sprintf("%s.%s.%s.%s.nip.io", ReportPrefix, instance.Name, instance.Namespace, ideIngress.Status.LoadBalancer.Ingress[0].IP)
-			ideIngress.Spec.Rules[2].Host = fmt.Sprintf("%s.%s.%s.%s.nip.io", ConfigPrefix, instance.Name, instance.Namespace, ideIngress.Status.LoadBalancer.Ingress[0].IP)
-			if err := r.client.Update(context.TODO(), ideIngress); err != nil {
+			if hosts[0] == "" {
+				if ideIngress.Spec.Rules[0].Host == IngressDefaultHost {
+					if err := r.setCondition(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "ingress for ide is not ready", "ingress didn't get external address yet"); err != nil {
+						return err
+					}
+				}
+			} else if hosts[0] != ideIngress.Spec.Rules[0].Host || hosts[1] != ideIngress.Spec.Rules[1].Host || hosts[2] != ideIngress.Spec.Rules[2].Host {
+				for i, host := range hosts {
+					ideIngress.Spec.Rules[i].Host = host
+				}
+				if err := r.updateExposure(ideIngress); err != nil {
+					return err
+				}
+				hostsChanged = true
+				r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonHostAssigned, "Hosts %s are assigned to ingress %s", strings.Join(hosts, ", "), ideIngress.Name)
+			}
+		}
+		if !hostsChanged && len(ideIngress.Spec.Rules) == 3 && ideIngress.Spec.Rules[0].Host != IngressDefaultHost && ideIngress.Spec.Rules[1].Host != IngressDefaultHost && ideIngress.Spec.Rules[2].Host != IngressDefaultHost {
+			// IDE exposes the source code, so it is served over TLS if possible
+			tlsEnabled, err := r.applyIngressTls(instance, ideIngress, instance.Spec.To.TLSSecret)
+			if err != nil {
 				return err
 			}
-		} else if len(ideIngress.Spec.Rules) == 3 && ideIngress.Spec.Rules[0].Host != IngressDefaultHost && ideIngress.Spec.Rules[1].Host != IngressDefaultHost && ideIngress.Spec.Rules[2].Host != IngressDefaultHost {
+
 			// Update ingress url to a status field
-			ideUrl = fmt.Sprintf("http://%s", ideIngress.Spec.Rules[0].Host)
-			reportUrl = fmt.Sprintf("http://%s", ideIngress.Spec.Rules[1].Host)
+			ideUrl = fmt.Sprintf("%s://%s", urlScheme(tlsEnabled), ideIngress.Spec.Rules[0].Host)
+			reportUrl = fmt.Sprintf("%s://%s", urlScheme(tlsEnabled), ideIngress.Spec.Rules[1].Host)
 		}
 
 		// Generate Deployment only if ingress is ready
@@ -99,7 +136,8 @@ func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 			if err != nil {
 				return err
 			}
-			if err := utils.CheckAndCreateObject(ideDeploy, instance, r.client, r.scheme, false); err != nil {
+			// Updated if the editor image of the operator is changed
+			if err := utils.CheckAndUpdateObject(ideDeploy, instance, r.client, r.scheme); err != nil {
 				return err
 			}
 
@@ -127,7 +165,9 @@ func (r *ReconcileTupWAS) deployIdeReport(instance *tmaxv1.TupWAS) error {
 		if instance.Status.Editor == nil {
 			instance.Status.Editor = &tmaxv1.EditorStatus{}
 		}
-		instance.Status.Editor.Password = string(idePassword)
+		instance.Status.Editor.PasswordSecret = passwordSecret
+		// Access code is in the Secret, do not keep the plaintext set by the former versions
+		instance.Status.Editor.Password = ""
 		instance.Status.Editor.Url = ideUrl
 		instance.Status.ReportUrl = reportUrl
 	}
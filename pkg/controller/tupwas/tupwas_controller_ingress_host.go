@@ -0,0 +1,98 @@
+package tupwas
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+type ingressHostParams struct {
+	Prefix    string
+	Name      string
+	Namespace string
+	Address   string
+}
+
+// Generate an ingress host from the host template
+// Returns an empty string if the template needs the address of the load balancer, which is not assigned yet
+func ingressHost(instance *tmaxv1.TupWAS, ing *networkingv1beta1.Ingress, prefix string) (string, error) {
+	tmpl, err := template.New("host").Option("missingkey=error").Parse(instance.GenIngressHostTemplate())
+	if err != nil {
+		return "", err
+	}
+
+	// Load balancer may have an IP or a hostname
+	address := ""
+	if lb := ing.Status.LoadBalancer.Ingress; len(lb) != 0 {
+		address = lb[0].IP
+		if address == "" {
+			address = lb[0].Hostname
+		}
+	}
+	if address == "" && templateUsesField(tmpl.Tree.Root, "Address") {
+		return "", nil
+	}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl.Execute(buf, ingressHostParams{Prefix: prefix, Name: instance.Name, Namespace: instance.Namespace, Address: address}); err != nil {
+		return "", err
+	}
+
+	host := buf.String()
+	if errs := validation.IsDNS1123Subdomain(host); len(errs) != 0 {
+		return "", fmt.Errorf("host %s is invalid: %s", host, strings.Join(errs, ", "))
+	}
+	return host, nil
+}
+
+// Whether the parsed template refers to the field (e.g., {{.Address}} or {{if .Address}}) of the data
+func templateUsesField(node parse.Node, field string) bool {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Nodes {
+			if templateUsesField(c, field) {
+				return true
+			}
+		}
+	case *parse.ActionNode:
+		return templateUsesField(n.Pipe, field)
+	case *parse.PipeNode:
+		if n == nil {
+			return false
+		}
+		for _, c := range n.Cmds {
+			if templateUsesField(c, field) {
+				return true
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if templateUsesField(arg, field) {
+				return true
+			}
+		}
+	case *parse.FieldNode:
+		return len(n.Ident) != 0 && n.Ident[0] == field
+	case *parse.ChainNode:
+		return templateUsesField(n.Node, field)
+	case *parse.IfNode:
+		return templateUsesField(n.Pipe, field) || templateUsesField(n.List, field) || templateUsesField(n.ElseList, field)
+	case *parse.RangeNode:
+		return templateUsesField(n.Pipe, field) || templateUsesField(n.List, field) || templateUsesField(n.ElseList, field)
+	case *parse.WithNode:
+		return templateUsesField(n.Pipe, field) || templateUsesField(n.List, field) || templateUsesField(n.ElseList, field)
+	case *parse.TemplateNode:
+		return templateUsesField(n.Pipe, field)
+	}
+	return false
+}
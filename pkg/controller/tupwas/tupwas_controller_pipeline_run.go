@@ -2,27 +2,43 @@ package tupwas
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
 	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
 	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (r *ReconcileTupWAS) watchPipelineRun(instance *tmaxv1.TupWAS) error {
-	// Watch Analyze PipelineRun
-	analyzePr := &tektonv1.PipelineRun{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenAnalyzePipelineName(), Namespace: instance.Namespace}, analyzePr); err != nil && !errors.IsNotFound(err) {
+	// PipelineRuns in the status, before watching them - to record events for the changes
+	prevAnalyzeName, prevAnalyzeCompleted := instance.Status.AnalyzePipelineRunName, instance.Status.LastAnalyzeCompletionTime != nil
+	prevBuildName, prevBuildCompleted := instance.Status.BuildPipelineRunName, instance.Status.LastBuildCompletionTime != nil
+
+	// Watch Analyze PipelineRun - the latest one
+	analyzePrs, err := r.listPipelineRuns(instance, tmaxv1.WasPipelineTypeAnalyze)
+	if err != nil {
 		return err
-	} else if err != nil && errors.IsNotFound(err) {
+	}
+	instance.Status.AnalyzeHistory = pipelineRunHistory(analyzePrs, instance.GenHistoryLimit())
+	if len(analyzePrs) == 0 {
 		instance.Status.AnalyzePipelineRunName = ""
 		instance.Status.SetCondition(tmaxv1.WasConditionKeyProjectAnalyzing, corev1.ConditionFalse, "PipelineRun is not running", "")
-	} else if err == nil {
-		instance.Status.AnalyzePipelineRunName = instance.GenAnalyzePipelineName()
+	} else {
+		analyzePr := &analyzePrs[0]
+		instance.Status.AnalyzePipelineRunName = analyzePr.Name
 		instance.Status.LastAnalyzeStartTime = analyzePr.Status.StartTime
 		instance.Status.LastAnalyzeCompletionTime = analyzePr.Status.CompletionTime
 		if len(analyzePr.Status.Conditions) != 0 {
 			condition := analyzePr.Status.Conditions[0]
+			// Cancelled by the user, but not stopped yet
+			if analyzePr.IsCancelled() && analyzePr.Status.CompletionTime == nil {
+				condition.Reason = string(tektonv1.PipelineRunReasonCancelled)
+			}
 			instance.Status.LastAnalyzeResult = condition.Reason
 
 			// Analyze Running
@@ -32,22 +48,55 @@ func (r *ReconcileTupWAS) watchPipelineRun(instance *tmaxv1.TupWAS) error {
 			}
 			instance.Status.SetCondition(tmaxv1.WasConditionKeyProjectAnalyzing, status, condition.Reason, condition.Message)
 		}
+
+		// Analysis summary - reset while analyzing
+		if analyzePr.Status.CompletionTime == nil {
+			instance.Status.AnalyzeSummary = nil
+		} else if summary, err := analyzeSummary(analyzePr); err != nil {
+			log.Error(err, "cannot parse analysis summary", "Namespace", instance.Namespace, "Name", instance.Name)
+		} else if summary != nil {
+			instance.Status.AnalyzeSummary = summary
+		}
+	}
+
+	// Source of the analysis report - kept even after the PipelineRuns are pruned
+	// Only the runs which actually cloned the source (having the commit result) count
+	for i := range analyzePrs {
+		if analyzePrs[i].Status.CompletionTime == nil {
+			continue
+		}
+		commit := pipelineTaskResult(&analyzePrs[i], string(tmaxv1.WasPipelineTaskNameClone), tmaxv1.WasTaskResultNameCommit)
+		if commit == "" {
+			continue
+		}
+		source := pipelineRunSource(&analyzePrs[i])
+		source.Commit = commit
+		instance.Status.AnalyzedSource = source
+		break
 	}
+	instance.Status.ReportStale = instance.Status.AnalyzedSource != nil && instance.IsSourceChanged(instance.Status.AnalyzedSource)
 
-	// Watch Build/Deploy PipelineRun
-	buildPr := &tektonv1.PipelineRun{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenBuildDeployPipelineName(), Namespace: instance.Namespace}, buildPr); err != nil && !errors.IsNotFound(err) {
+	// Watch Build/Deploy PipelineRun - the latest one
+	buildPrs, err := r.listPipelineRuns(instance, tmaxv1.WasPipelineTypeBuildDeploy)
+	if err != nil {
 		return err
-	} else if err != nil && errors.IsNotFound(err) {
+	}
+	instance.Status.BuildHistory = pipelineRunHistory(buildPrs, instance.GenHistoryLimit())
+	if len(buildPrs) == 0 {
 		instance.Status.BuildPipelineRunName = ""
 		instance.Status.SetCondition(tmaxv1.WasConditionKeyProjectRunning, corev1.ConditionFalse, "PipelineRun is not running", "")
 		instance.Status.SetCondition(tmaxv1.WasConditionKeyProjectSucceeded, corev1.ConditionFalse, "", "")
-	} else if err == nil {
-		instance.Status.BuildPipelineRunName = instance.GenBuildDeployPipelineName()
+	} else {
+		buildPr := &buildPrs[0]
+		instance.Status.BuildPipelineRunName = buildPr.Name
 		instance.Status.LastBuildStartTime = buildPr.Status.StartTime
 		instance.Status.LastBuildCompletionTime = buildPr.Status.CompletionTime
 		if len(buildPr.Status.Conditions) != 0 {
 			condition := buildPr.Status.Conditions[0]
+			// Cancelled by the user, but not stopped yet
+			if buildPr.IsCancelled() && buildPr.Status.CompletionTime == nil {
+				condition.Reason = string(tektonv1.PipelineRunReasonCancelled)
+			}
 			instance.Status.LastBuildResult = condition.Reason
 
 			// Build/Deploy Running
@@ -66,5 +115,186 @@ func (r *ReconcileTupWAS) watchPipelineRun(instance *tmaxv1.TupWAS) error {
 		}
 	}
 
+	// Images built by the Build/Deploy PipelineRuns, kept even after the PipelineRuns are pruned
+	instance.Status.ImageHistory = imageHistory(instance, buildPrs, analyzePrs)
+
+	// Progress of each pipeline task
+	var latestAnalyzePr, latestBuildPr *tektonv1.PipelineRun
+	if len(analyzePrs) != 0 {
+		latestAnalyzePr = &analyzePrs[0]
+	}
+	if len(buildPrs) != 0 {
+		latestBuildPr = &buildPrs[0]
+	}
+	r.recordPipelineRunEvents(instance, tmaxv1.WasPipelineTypeAnalyze, prevAnalyzeName, prevAnalyzeCompleted, latestAnalyzePr)
+	r.recordPipelineRunEvents(instance, tmaxv1.WasPipelineTypeBuildDeploy, prevBuildName, prevBuildCompleted, latestBuildPr)
+	instance.Status.Progress = append(
+		tmaxv1.GenTaskProgress([]string{string(tmaxv1.WasPipelineTaskNameClone), string(tmaxv1.WasPipelineTaskNameAnalyze)}, latestAnalyzePr),
+		tmaxv1.GenTaskProgress([]string{string(tmaxv1.WasPipelineTaskNameBuild), string(tmaxv1.WasPipelineTaskNameDeploy)}, latestBuildPr)...,
+	)
+
+	// Delete PipelineRuns exceeding the history limit
+	if err := r.prunePipelineRuns(analyzePrs, instance.GenHistoryLimit()); err != nil {
+		return err
+	}
+	if err := r.prunePipelineRuns(buildPrs, instance.GenHistoryLimit()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// List PipelineRuns of the pipeline type (WasPipelineType*), the latest first
+func (r *ReconcileTupWAS) listPipelineRuns(instance *tmaxv1.TupWAS, pipelineType string) ([]tektonv1.PipelineRun, error) {
+	prList := &tektonv1.PipelineRunList{}
+	if err := r.client.List(context.TODO(), prList, client.InNamespace(instance.Namespace), client.MatchingLabels(instance.GenPipelineRunLabels(pipelineType))); err != nil {
+		return nil, err
+	}
+
+	prs := prList.Items
+	SortPipelineRuns(prs)
+
+	return prs, nil
+}
+
+// Sort PipelineRuns, the latest first - ties of the creation timestamp are broken by the sequence annotation
+func SortPipelineRuns(prs []tektonv1.PipelineRun) {
+	sort.Slice(prs, func(i, j int) bool {
+		if prs[i].CreationTimestamp.Equal(&prs[j].CreationTimestamp) {
+			return pipelineRunSequence(&prs[i]) > pipelineRunSequence(&prs[j])
+		}
+		return prs[j].CreationTimestamp.Before(&prs[i].CreationTimestamp)
+	})
+}
+
+// Sequence of the PipelineRun (WasAnnotationSequence), 0 if not set
+func pipelineRunSequence(pr *tektonv1.PipelineRun) int64 {
+	seq, err := strconv.ParseInt(pr.Annotations[tmaxv1.WasAnnotationSequence], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return seq
+}
+
+// Delete completed PipelineRuns exceeding the limit - prs should be sorted, the latest first
+func (r *ReconcileTupWAS) prunePipelineRuns(prs []tektonv1.PipelineRun, limit int) error {
+	for i := limit; i < len(prs); i++ {
+		if prs[i].Status.CompletionTime == nil {
+			continue
+		}
+		if err := r.client.Delete(context.TODO(), &prs[i]); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
 	return nil
 }
+
+func pipelineRunHistory(prs []tektonv1.PipelineRun, limit int) []tmaxv1.PipelineRunHistory {
+	var history []tmaxv1.PipelineRunHistory
+	for i, pr := range prs {
+		if i >= limit {
+			break
+		}
+		h := tmaxv1.PipelineRunHistory{
+			Name:           pr.Name,
+			StartTime:      pr.Status.StartTime,
+			CompletionTime: pr.Status.CompletionTime,
+		}
+		if len(pr.Status.Conditions) != 0 {
+			h.Result = pr.Status.Conditions[0].Reason
+		}
+		history = append(history, h)
+	}
+
+	return history
+}
+
+// Merge images built by successful Build/Deploy PipelineRuns into the image history, the latest first
+// prs should be sorted, the latest first
+func imageHistory(instance *tmaxv1.TupWAS, buildPrs, analyzePrs []tektonv1.PipelineRun) []tmaxv1.ImageHistory {
+	recorded := map[string]bool{}
+	for _, h := range instance.Status.ImageHistory {
+		recorded[h.PipelineRun] = true
+	}
+
+	var history []tmaxv1.ImageHistory
+	for _, pr := range buildPrs {
+		if recorded[pr.Name] || len(pr.Status.Conditions) == 0 || pr.Status.Conditions[0].Reason != string(tektonv1.PipelineRunReasonSuccessful) {
+			continue
+		}
+		// Deploy PipelineRuns (rollback) do not build images
+		image := pipelineTaskResult(&pr, string(tmaxv1.WasPipelineTaskNameBuild), tmaxv1.WasTaskResultNameImageUrl)
+		if image == "" {
+			continue
+		}
+		history = append(history, tmaxv1.ImageHistory{
+			Image:       image,
+			Digest:      pipelineTaskResult(&pr, string(tmaxv1.WasPipelineTaskNameBuild), tmaxv1.WasTaskResultNameImageDigest),
+			Revision:    builtRevision(instance, &pr, analyzePrs),
+			Time:        pr.Status.CompletionTime,
+			PipelineRun: pr.Name,
+		})
+	}
+	history = append(history, instance.Status.ImageHistory...)
+
+	if len(history) > tmaxv1.WasImageHistoryLimit {
+		history = history[:tmaxv1.WasImageHistoryLimit]
+	}
+	return history
+}
+
+// Source is cloned by the Analyze PipelineRun, so the commit is the one cloned by the latest analysis before the build
+func builtRevision(instance *tmaxv1.TupWAS, buildPr *tektonv1.PipelineRun, analyzePrs []tektonv1.PipelineRun) string {
+	for _, pr := range analyzePrs {
+		if pr.Status.CompletionTime == nil || buildPr.Status.StartTime == nil || buildPr.Status.StartTime.Before(pr.Status.CompletionTime) {
+			continue
+		}
+		if commit := pipelineTaskResult(&pr, string(tmaxv1.WasPipelineTaskNameClone), tmaxv1.WasTaskResultNameCommit); commit != "" {
+			return commit
+		}
+	}
+	return instance.GenGitRevision()
+}
+
+// Git source given to the Analyze PipelineRun
+func pipelineRunSource(pr *tektonv1.PipelineRun) *tmaxv1.AnalyzedSource {
+	source := &tmaxv1.AnalyzedSource{}
+	for _, p := range pr.Spec.Params {
+		switch p.Name {
+		case tmaxv1.WasPipelineParamNameGitUrl:
+			source.Url = p.Value.StringVal
+		case tmaxv1.WasPipelineParamNameGitRev:
+			source.Revision = p.Value.StringVal
+		}
+	}
+	return source
+}
+
+// Result of the pipeline task of the PipelineRun - returns empty string if there is no result
+func pipelineTaskResult(pr *tektonv1.PipelineRun, taskName, resultName string) string {
+	for _, tr := range pr.Status.TaskRuns {
+		if tr.PipelineTaskName != taskName || tr.Status == nil {
+			continue
+		}
+		for _, result := range tr.Status.TaskRunResults {
+			if result.Name == resultName {
+				return strings.TrimSpace(result.Value)
+			}
+		}
+	}
+	return ""
+}
+
+// Get analysis summary from the result of analyze task - returns nil if there is no result
+func analyzeSummary(pr *tektonv1.PipelineRun) (*tmaxv1.AnalyzeSummary, error) {
+	result := pipelineTaskResult(pr, string(tmaxv1.WasPipelineTaskNameAnalyze), tmaxv1.WasTaskResultNameSummary)
+	if result == "" {
+		return nil, nil
+	}
+	summary := &tmaxv1.AnalyzeSummary{}
+	if err := json.Unmarshal([]byte(result), summary); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
@@ -0,0 +1,29 @@
+package tupwas
+
+import (
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Set GatePassed condition, evaluating the quality gate against the last analysis
+func (r *ReconcileTupWAS) checkQualityGate(instance *tmaxv1.TupWAS) {
+	stat := corev1.ConditionUnknown
+	reason := "NotAnalyzed"
+	msg := "analysis is not completed successfully"
+
+	if instance.Status.LastAnalyzeCompletionTime != nil && instance.Status.LastAnalyzeResult == string(tektonv1.PipelineRunReasonSuccessful) {
+		if passed, violation := instance.EvaluateQualityGate(); passed {
+			stat, reason, msg = corev1.ConditionTrue, "Passed", ""
+		} else {
+			stat, reason, msg = corev1.ConditionFalse, "Failed", violation
+		}
+	}
+
+	cond, found := instance.Status.GetCondition(tmaxv1.WasConditionKeyGatePassed)
+	if found && cond.Status == stat && string(cond.Reason) == reason && cond.Message == msg {
+		return
+	}
+	instance.Status.Conditions = instance.Status.SetCondition(tmaxv1.WasConditionKeyGatePassed, stat, reason, msg)
+}
@@ -8,6 +8,26 @@ import (
 )
 
 func (r *ReconcileTupWAS) deployResources(instance *tmaxv1.TupWAS) error {
+	// Validate source WAS type
+	if _, err := instance.GenAnalyzeParam(); err != nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "source type not supported", err.Error()); err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// Builder profile for the target WAS type
+	profile, err := r.getBuilderProfile(instance)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "builder profile not found", fmt.Sprintf("there is no TupBuilderProfile for target type %s", instance.Spec.To.Type)); err != nil {
+			return err
+		}
+		return nil
+	}
+
 	// Set Project Ready first
 	currentReadyState, found := instance.Status.GetCondition(tmaxv1.WasConditionKeyProjectReady)
 	if !found {
@@ -19,7 +39,7 @@ func (r *ReconcileTupWAS) deployResources(instance *tmaxv1.TupWAS) error {
 		}
 	}
 
-	// PVC for git & repo
+	// PVC for git & repo - only created, as its spec cannot be changed without losing the data
 	pvc, err := gitReportPVC(instance)
 	if err != nil {
 		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating PVC", err.Error()); err != nil {
@@ -32,44 +52,48 @@ func (r *ReconcileTupWAS) deployResources(instance *tmaxv1.TupWAS) error {
 	}
 
 	// ConfigMap for WAS deployment
-	wasConfigMap, err := wasDeployConfigMap(instance)
-	if err != nil {
-		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating configMap", err.Error()); err != nil {
-			return err
-		}
-		return err
-	}
-	if err := r.createAndUpdateStatus(wasConfigMap, instance, "error getting/creating configMap"); err != nil {
+	if err := r.deployWasConfigMap(instance, profile); err != nil {
 		return err
 	}
 
 	// ServiceAccount for WAS deployment
 	wasDeploySa := wasDeployServiceAccount(instance)
-	if err := r.createAndUpdateStatus(wasDeploySa, instance, "error getting/creating serviceAccount"); err != nil {
+	if err := r.applyAndUpdateStatus(wasDeploySa, instance, "error getting/creating serviceAccount"); err != nil {
+		return err
+	}
+
+	// Git credential for cloning the source
+	if err := r.deployGitSecret(instance); err != nil {
 		return err
 	}
 
 	// RoleBinding for WAS deployment
 	wasDeployRb := wasDeployRoleBinding(instance)
-	if err := r.createAndUpdateStatus(wasDeployRb, instance, "error getting/creating roleBinding"); err != nil {
+	if err := r.applyAndUpdateStatus(wasDeployRb, instance, "error getting/creating roleBinding"); err != nil {
 		return err
 	}
 
 	// Pipeline 1 - Analyze
 	analyzePipeline := analyzePipeline(instance)
-	if err := r.createAndUpdateStatus(analyzePipeline, instance, "error getting/creating pipeline"); err != nil {
+	if err := r.applyAndUpdateStatus(analyzePipeline, instance, "error getting/creating pipeline"); err != nil {
 		return err
 	}
 
 	// Pipeline 2 - Build/Deploy
-	buildDeployPipeline, err := buildDeployPipeline(instance)
+	buildDeployPipeline, err := buildDeployPipeline(instance, profile)
 	if err != nil {
 		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating pipeline", err.Error()); err != nil {
 			return err
 		}
 		return err
 	}
-	if err := r.createAndUpdateStatus(buildDeployPipeline, instance, "error getting/creating pipeline"); err != nil {
+	if err := r.applyAndUpdateStatus(buildDeployPipeline, instance, "error getting/creating pipeline"); err != nil {
+		return err
+	}
+
+	// Pipeline 3 - Deploy, to roll back to an image built before
+	deployPipeline := deployPipeline(instance)
+	if err := r.applyAndUpdateStatus(deployPipeline, instance, "error getting/creating pipeline"); err != nil {
 		return err
 	}
 
@@ -78,27 +102,54 @@ func (r *ReconcileTupWAS) deployResources(instance *tmaxv1.TupWAS) error {
 		return err
 	}
 
+	// Roll out the WAS image, according to the rollout strategy
+	if err := r.manageWasRollout(instance); err != nil {
+		return err
+	}
+
 	// If Build/Deploy Complete, deploy WAS service/ingress
 	if instance.Status.LastBuildCompletionTime != nil && instance.Status.LastBuildResult == string(tektonv1.PipelineRunReasonSuccessful) {
-		if err := r.deployWasNetwork(instance); err != nil {
+		if err := r.deployWasNetwork(instance, profile); err != nil {
+			return err
+		}
+		if err := r.deployWasAutoscaler(instance); err != nil {
 			return err
 		}
 	}
 
 	// Manage WAS network (Service/Ingress)
-	if err := r.manageWasNetwork(instance); err != nil {
+	if err := r.manageWasNetwork(instance, profile); err != nil {
 		return err
 	}
 
 	// If it is ready (only once when analyze is not executed at all) and not analyzing, launch analyze once
 	readyCond, readyCondFound := instance.Status.GetCondition(tmaxv1.WasConditionKeyProjectReady)
 	analyzeCond, analyzeCondFound := instance.Status.GetCondition(tmaxv1.WasConditionKeyProjectAnalyzing)
-	if readyCondFound && analyzeCondFound && instance.Status.LastAnalyzeStartTime == nil && readyCond.Status == corev1.ConditionTrue && analyzeCond.Status == corev1.ConditionFalse {
-		pr := AnalyzePipelineRun(instance)
+	if readyCondFound && analyzeCondFound && instance.Status.LastAnalyzeStartTime == nil && len(instance.Status.AnalyzeHistory) == 0 && readyCond.Status == corev1.ConditionTrue && analyzeCond.Status == corev1.ConditionFalse {
+		pr, err := AnalyzePipelineRun(instance)
+		if err != nil {
+			return err
+		}
+		// Fixed name for the first analysis, not to be launched twice
+		pr.GenerateName = ""
+		pr.Name = instance.GenAnalyzePipelineName() + "-initial"
+		if instance.IsBuildDeployAfterAnalysis(false) {
+			MarkBuildDeployAfter(pr)
+		}
 		if err := r.createAndUpdateStatus(pr, instance, "cannot create pipelineRun"); err != nil {
 			return err
 		}
 	}
 
+	// Analyze again if the source is changed
+	if err := r.reanalyzeOnSourceChange(instance); err != nil {
+		return err
+	}
+
+	// Build/Deploy after the analysis triggered by git webhook, or according to spec.autoRun
+	if err := r.buildDeployAfterAnalyze(instance); err != nil {
+		return err
+	}
+
 	return nil
 }
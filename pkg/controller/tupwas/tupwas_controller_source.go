@@ -0,0 +1,48 @@
+package tupwas
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Launch analysis again if the git source (url/revision) is changed after the latest analysis
+// The first analysis is launched by deployResources, so it is only for the TupWAS analyzed before
+func (r *ReconcileTupWAS) reanalyzeOnSourceChange(instance *tmaxv1.TupWAS) error {
+	if instance.Status.AnalyzedSource == nil {
+		return nil
+	}
+
+	analyzePrs, err := r.listPipelineRuns(instance, tmaxv1.WasPipelineTypeAnalyze)
+	if err != nil {
+		return err
+	}
+	// The latest analysis may already be for the changed source, or is still running
+	if len(analyzePrs) == 0 || analyzePrs[0].Status.CompletionTime == nil || !instance.IsSourceChanged(pipelineRunSource(&analyzePrs[0])) {
+		return nil
+	}
+
+	readyCond, found := instance.Status.GetCondition(tmaxv1.WasConditionKeyProjectReady)
+	if !found || readyCond.Status != corev1.ConditionTrue {
+		return nil
+	}
+
+	pr, err := AnalyzePipelineRun(instance)
+	if err != nil {
+		return err
+	}
+	// Fixed name for the generation, not to be launched twice
+	pr.GenerateName = ""
+	pr.Name = fmt.Sprintf("%s-gen%d", instance.GenAnalyzePipelineName(), instance.Generation)
+	if instance.IsBuildDeployAfterAnalysis(false) {
+		MarkBuildDeployAfter(pr)
+	}
+	if err := r.createAndUpdateStatus(pr, instance, "cannot create pipelineRun"); err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("Source of TupWAS %s/%s is changed, launched analysis %s", instance.Namespace, instance.Name, pr.Name))
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package tupwas
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tmax-cloud/l2c-operator/internal"
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+const (
+	// Issued secret also contains the CA certificate, for the clients to trust
+	TlsSecretKeyCa = "ca.crt"
+)
+
+// Set TLS of the ingress, using the given secret or the certificate issued by the CA of the operator
+// Returns whether the ingress is served over TLS
+func (r *ReconcileTupWAS) applyIngressTls(instance *tmaxv1.TupWAS, ing *networkingv1beta1.Ingress, secretName string) (bool, error) {
+	var hosts []string
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host != "" && rule.Host != IngressDefaultHost {
+			hosts = append(hosts, rule.Host)
+		}
+	}
+	if len(hosts) == 0 {
+		return false, nil
+	}
+
+	if secretName == "" {
+		// Neither the secret nor the CA is given
+		if internal.TlsCaSecret == "" {
+			return false, nil
+		}
+		secretName = ing.Name + "-tls"
+		if err := r.issueCert(instance, secretName, hosts); err != nil {
+			return false, err
+		}
+	}
+
+	desiredTls := []networkingv1beta1.IngressTLS{{Hosts: hosts, SecretName: secretName}}
+	if !reflect.DeepEqual(ing.Spec.TLS, desiredTls) {
+		ing.Spec.TLS = desiredTls
+		if err := r.updateExposure(ing); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// Issue a certificate for the hosts to the secret, if it does not cover the hosts or is about to expire
+func (r *ReconcileTupWAS) issueCert(instance *tmaxv1.TupWAS, secretName string, hosts []string) error {
+	secret := &corev1.Secret{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: secretName, Namespace: instance.Namespace}, secret)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exist := err == nil
+	if exist && utils.CertCovers(secret.Data[corev1.TLSCertKey], hosts, time.Now().AddDate(0, 0, tmaxv1.WasCertRenewalDays)) {
+		return nil
+	}
+
+	caSecret, err := r.getCaSecret()
+	if err != nil {
+		return err
+	}
+	crt, key, err := utils.IssueCert(caSecret.Data[corev1.TLSCertKey], caSecret.Data[corev1.TLSPrivateKeyKey], hosts, time.Now().AddDate(0, 0, tmaxv1.WasCertValidDays))
+	if err != nil {
+		return err
+	}
+	data := map[string][]byte{
+		corev1.TLSCertKey:       crt,
+		corev1.TLSPrivateKeyKey: key,
+		TlsSecretKeyCa:          caSecret.Data[corev1.TLSCertKey],
+	}
+
+	secret = &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: instance.Namespace,
+			Labels:    instance.GenLabels(),
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: data,
+	}
+	return utils.CheckAndUpdateObject(secret, instance, r.client, r.scheme)
+}
+
+// CA secret is given as <namespace>/<name>, or <name> in the namespace of the operator
+func (r *ReconcileTupWAS) getCaSecret() (*corev1.Secret, error) {
+	namespacedName := types.NamespacedName{Name: internal.TlsCaSecret}
+	if tokens := strings.SplitN(internal.TlsCaSecret, "/", 2); len(tokens) == 2 {
+		namespacedName.Namespace, namespacedName.Name = tokens[0], tokens[1]
+	} else {
+		ns, err := utils.Namespace()
+		if err != nil {
+			return nil, err
+		}
+		namespacedName.Namespace = ns
+	}
+
+	caSecret := &corev1.Secret{}
+	if err := r.client.Get(context.TODO(), namespacedName, caSecret); err != nil {
+		return nil, fmt.Errorf("cannot get CA secret %s: %s", namespacedName.String(), err.Error())
+	}
+	return caSecret, nil
+}
+
+func urlScheme(tlsEnabled bool) string {
+	if tlsEnabled {
+		return "https"
+	}
+	return "http"
+}
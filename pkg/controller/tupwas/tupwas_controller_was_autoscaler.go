@@ -0,0 +1,48 @@
+package tupwas
+
+import (
+	"context"
+
+	autoscalingv2beta2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Deploy HorizontalPodAutoscaler for the WAS deployment, keep it up-to-date with spec.to.autoscaling
+// and report the replicas in the status
+func (r *ReconcileTupWAS) deployWasAutoscaler(instance *tmaxv1.TupWAS) error {
+	hpa := &autoscalingv2beta2.HorizontalPodAutoscaler{}
+	err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenWasResourceName(), Namespace: instance.Namespace}, hpa)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	exist := err == nil
+
+	// Autoscaling is disabled
+	if instance.Spec.To.Autoscaling == nil {
+		instance.Status.CurrentReplicas = 0
+		instance.Status.DesiredReplicas = 0
+		if exist && metav1.IsControlledBy(hpa, instance) {
+			if err := r.client.Delete(context.TODO(), hpa); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	}
+
+	desiredHpa := wasAutoscaler(instance)
+	if err := r.applyAndUpdateStatus(desiredHpa, instance, "error getting/creating horizontalPodAutoscaler"); err != nil {
+		return err
+	}
+	if !exist {
+		return nil
+	}
+
+	instance.Status.CurrentReplicas = hpa.Status.CurrentReplicas
+	instance.Status.DesiredReplicas = hpa.Status.DesiredReplicas
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package tupwas
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Deploy ConfigMaps for WAS deployment spec of each track, and keep them up-to-date with the TupWAS
+// The data is also restored if it is modified by others
+func (r *ReconcileTupWAS) deployWasConfigMap(instance *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile) error {
+	for _, track := range instance.GenRolloutTracks() {
+		desiredCm, err := wasDeployConfigMap(instance, profile, track)
+		if err != nil {
+			if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating configMap", err.Error()); err != nil {
+				return err
+			}
+			return err
+		}
+		if err := r.applyAndUpdateStatus(desiredCm, instance, "error getting/creating configMap"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -5,22 +5,27 @@ import (
 	"fmt"
 	"github.com/tmax-cloud/l2c-operator/internal/utils"
 	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func (r *ReconcileTupWAS) deployWasNetwork(instance *tmaxv1.TupWAS) error {
+const (
+	WasPrefix = "was"
+)
+
+func (r *ReconcileTupWAS) deployWasNetwork(instance *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile) error {
 	// Service for WAS deployment
-	wasService, err := wasService(instance)
+	wasService, err := wasService(instance, profile)
 	if err != nil {
 		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating service", err.Error()); err != nil {
 			return err
 		}
 		return err
 	}
-	if err := utils.CheckAndCreateObject(wasService, nil, r.client, r.scheme, false); err != nil {
+	if err := utils.CheckAndUpdateObject(wasService, nil, r.client, r.scheme); err != nil {
 		if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating service", err.Error()); err != nil {
 			return err
 		}
@@ -29,35 +34,59 @@ func (r *ReconcileTupWAS) deployWasNetwork(instance *tmaxv1.TupWAS) error {
 
 	// Ingress for WAS deployment - only if service type is Ingress(=default)
 	if instance.Spec.To.ServiceType == "" || instance.Spec.To.ServiceType == tmaxv1.WasServiceTypeIngress {
-		wasIngress, err := wasIngress(instance)
+		wasIngress, err := wasIngress(instance, profile)
 		if err != nil {
 			if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating ingress", err.Error()); err != nil {
 				return err
 			}
 			return err
 		}
-		if err := utils.CheckAndCreateObject(wasIngress, nil, r.client, r.scheme, false); err != nil {
+		exposures, err := r.exposureObjects(wasIngress)
+		if err != nil {
 			if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating ingress", err.Error()); err != nil {
 				return err
 			}
 			return err
 		}
+		// Hosts/TLS in the spec are set afterwards, by updateExposure
+		for _, exposure := range exposures {
+			if err := utils.CheckAndUpdateObject(exposure, nil, r.client, r.scheme, "spec"); err != nil {
+				if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "error getting/creating ingress", err.Error()); err != nil {
+					return err
+				}
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-func (r *ReconcileTupWAS) manageWasNetwork(instance *tmaxv1.TupWAS) error {
+func (r *ReconcileTupWAS) manageWasNetwork(instance *tmaxv1.TupWAS, profile *tmaxv1.TupBuilderProfile) error {
 	// Update ingress - apply host
-	wasIngress := &networkingv1beta1.Ingress{}
-	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenWasResourceName(), Namespace: instance.Namespace}, wasIngress); err != nil && !errors.IsNotFound(err) {
+	wasIngress, err := wasIngress(instance, profile)
+	if err != nil {
+		return err
+	}
+	if err := r.getExposure(wasIngress); err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if err == nil {
-		if len(wasIngress.Status.LoadBalancer.Ingress) != 0 && len(wasIngress.Spec.Rules) == 1 && wasIngress.Spec.Rules[0].Host == IngressDefaultHost {
-			// If Loadbalancer is given to the ingress, but host is not set, set host!
-			wasIngress.Spec.Rules[0].Host = fmt.Sprintf("%s.%s.%s.nip.io", instance.Name, instance.Namespace, wasIngress.Status.LoadBalancer.Ingress[0].IP)
-			if err := r.client.Update(context.TODO(), wasIngress); err != nil {
-				return err
+		if len(wasIngress.Spec.Rules) == 1 {
+			// Set host (after the load balancer is given, if the host template needs its address)
+			// It is generated every time, so that the changes of the host template are applied
+			host, err := ingressHost(instance, wasIngress, WasPrefix)
+			if err != nil {
+				if err := r.updateErrorStatus(instance, tmaxv1.WasConditionKeyProjectReady, corev1.ConditionFalse, "invalid ingress host", err.Error()); err != nil {
+					return err
+				}
+				return nil
+			}
+			if host != "" && host != wasIngress.Spec.Rules[0].Host {
+				wasIngress.Spec.Rules[0].Host = host
+				if err := r.updateExposure(wasIngress); err != nil {
+					return err
+				}
+				r.recorder.Eventf(instance, corev1.EventTypeNormal, tmaxv1.EventReasonHostAssigned, "Host %s is assigned to ingress %s", host, wasIngress.Name)
 			}
 		}
 	}
@@ -71,7 +100,11 @@ func (r *ReconcileTupWAS) manageWasNetwork(instance *tmaxv1.TupWAS) error {
 	switch instance.Spec.To.ServiceType {
 	case tmaxv1.WasServiceTypeIngress, "":
 		if len(wasIngress.Spec.Rules) == 1 && wasIngress.Spec.Rules[0].Host != IngressDefaultHost {
-			wasUrl = fmt.Sprintf("http://%s", wasIngress.Spec.Rules[0].Host)
+			tlsEnabled, err := r.applyIngressTls(instance, wasIngress, instance.Spec.To.TLSSecret)
+			if err != nil {
+				return err
+			}
+			wasUrl = fmt.Sprintf("%s://%s", urlScheme(tlsEnabled), wasIngress.Spec.Rules[0].Host)
 		}
 	case tmaxv1.WasServiceTypeLoadBalancer:
 		if len(wasService.Status.LoadBalancer.Ingress) > 0 && len(wasService.Spec.Ports) > 0 {
@@ -103,7 +136,30 @@ func (r *ReconcileTupWAS) manageWasNetwork(instance *tmaxv1.TupWAS) error {
 		}
 		return nil
 	}
+
+	// WAS URL is only available when the WAS pods are ready
+	ready, err := r.isWasReady(instance)
+	if err != nil {
+		return err
+	}
+	if !ready {
+		wasUrl = ""
+	}
 	instance.Status.WasUrl = wasUrl
 
 	return nil
 }
+
+// Whether any of the WAS pods (deployed by the build/deploy pipeline) passed the readiness probe
+func (r *ReconcileTupWAS) isWasReady(instance *tmaxv1.TupWAS) (bool, error) {
+	deploys := &appsv1.DeploymentList{}
+	if err := r.client.List(context.TODO(), deploys, client.InNamespace(instance.Namespace), client.MatchingLabels(instance.GenWasLabels())); err != nil {
+		return false, err
+	}
+	for _, d := range deploys.Items {
+		if d.Status.ReadyReplicas > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
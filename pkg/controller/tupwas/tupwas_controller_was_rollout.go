@@ -0,0 +1,296 @@
+package tupwas
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+const (
+	// Reason of Progressing condition of a deployment, whose new pods do not get ready in progressDeadlineSeconds
+	DeploymentReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+)
+
+// Roll out the WAS image deployed by the build/deploy pipeline, according to spec.to.rolloutStrategy
+// A new image is promoted if its pods get ready, or rolled back to status.rollout.stableImage if they fail the health check
+func (r *ReconcileTupWAS) manageWasRollout(instance *tmaxv1.TupWAS) error {
+	if instance.Spec.To.RolloutStrategy == tmaxv1.WasRolloutStrategyBlueGreen {
+		if err := r.rolloutBlueGreen(instance); err != nil {
+			return err
+		}
+	} else {
+		if err := r.rolloutStable(instance); err != nil {
+			return err
+		}
+	}
+
+	// Canary deployment is checked even if the strategy is changed, not to be left behind
+	if err := r.rolloutCanary(instance); err != nil {
+		return err
+	}
+
+	return r.updateWasServiceSelector(instance)
+}
+
+// Stable deployment (named after the TupWAS) is updated in place by the deploy task, or by the canary promotion
+func (r *ReconcileTupWAS) rolloutStable(instance *tmaxv1.TupWAS) error {
+	deploy, err := r.getWasDeploy(instance, "")
+	if err != nil || deploy == nil {
+		return err
+	}
+
+	// Strategy type is not changed by applying the deployment spec, as rollingUpdate is defaulted by the server
+	strategyType := appsv1.RollingUpdateDeploymentStrategyType
+	if instance.Spec.To.RolloutStrategy == tmaxv1.WasRolloutStrategyRecreate {
+		strategyType = appsv1.RecreateDeploymentStrategyType
+	}
+	if deploy.Spec.Strategy.Type != strategyType {
+		deploy.Spec.Strategy = appsv1.DeploymentStrategy{Type: strategyType}
+		return r.client.Update(context.TODO(), deploy)
+	}
+
+	// Pods of the stable deployment deployed by the older versions do not have the track label, which the service may select
+	if deploy.Spec.Template.Labels[tmaxv1.WasLabelKeyTrack] != tmaxv1.WasTrackStable {
+		if deploy.Spec.Template.Labels == nil {
+			deploy.Spec.Template.Labels = map[string]string{}
+		}
+		deploy.Spec.Template.Labels[tmaxv1.WasLabelKeyTrack] = tmaxv1.WasTrackStable
+		return r.client.Update(context.TODO(), deploy)
+	}
+
+	stableImage, activeTrack := "", ""
+	if instance.Status.Rollout != nil {
+		stableImage, activeTrack = instance.Status.Rollout.StableImage, instance.Status.Rollout.ActiveTrack
+	}
+	image := deployImage(deploy)
+	if image == stableImage && activeTrack == "" {
+		return nil
+	}
+
+	switch {
+	case deployRolledOut(deploy):
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhasePromoted, fmt.Sprintf("%s is rolled out", image))
+		instance.Status.Rollout.StableImage = image
+
+		// Blue/green deployments are not used anymore, once the stable one is rolled out
+		if activeTrack != "" {
+			instance.Status.Rollout.ActiveTrack = ""
+			if err := r.updateWasServiceSelector(instance); err != nil {
+				return err
+			}
+			for _, track := range []string{tmaxv1.WasTrackBlue, tmaxv1.WasTrackGreen} {
+				if err := r.deleteWasDeploy(instance, track); err != nil {
+					return err
+				}
+			}
+		}
+	case deployFailed(deploy) && stableImage != "" && image != stableImage:
+		deploy.Spec.Template.Spec.Containers[0].Image = stableImage
+		if err := r.client.Update(context.TODO(), deploy); err != nil {
+			return err
+		}
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseRolledBack, fmt.Sprintf("%s failed the health check, rolled back to %s", image, stableImage))
+	default:
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseProgressing, fmt.Sprintf("%s is being rolled out", image))
+	}
+
+	return nil
+}
+
+// New image is deployed to the inactive track (blue or green), and the service is switched to it after it gets ready
+func (r *ReconcileTupWAS) rolloutBlueGreen(instance *tmaxv1.TupWAS) error {
+	track := instance.GenRolloutTrack()
+	deploy, err := r.getWasDeploy(instance, track)
+	if err != nil || deploy == nil {
+		return err
+	}
+
+	stableImage := ""
+	if instance.Status.Rollout != nil {
+		stableImage = instance.Status.Rollout.StableImage
+	}
+	image := deployImage(deploy)
+	// Inactive deployment is scaled down to 0, if it is not being rolled out
+	if deployReplicas(deploy) == 0 || image == stableImage {
+		return nil
+	}
+
+	switch {
+	case deployFailed(deploy):
+		// Service still routes to the active track, scale down the failed one
+		zero := int32(0)
+		deploy.Spec.Replicas = &zero
+		if stableImage != "" {
+			deploy.Spec.Template.Spec.Containers[0].Image = stableImage
+		}
+		if err := r.client.Update(context.TODO(), deploy); err != nil {
+			return err
+		}
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseRolledBack, fmt.Sprintf("%s failed the health check, service still routes to %s", image, stableImage))
+	case deployRolledOut(deploy):
+		previousName := instance.GenWasActiveDeployName()
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhasePromoted, fmt.Sprintf("%s is rolled out, service is switched to %s", image, track))
+		instance.Status.Rollout.StableImage = image
+		instance.Status.Rollout.ActiveTrack = track
+
+		// Switch the service first, and then scale down the previous deployment
+		if err := r.updateWasServiceSelector(instance); err != nil {
+			return err
+		}
+		previous := &appsv1.Deployment{}
+		if err := r.client.Get(context.TODO(), types.NamespacedName{Name: previousName, Namespace: instance.Namespace}, previous); err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if err == nil {
+			// The stable deployment is not used for blueGreen strategy
+			if previous.Name == instance.GenWasDeployName("") {
+				if err := r.client.Delete(context.TODO(), previous); err != nil && !errors.IsNotFound(err) {
+					return err
+				}
+			} else {
+				zero := int32(0)
+				previous.Spec.Replicas = &zero
+				if err := r.client.Update(context.TODO(), previous); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseProgressing, fmt.Sprintf("%s is being rolled out to %s", image, track))
+	}
+
+	return nil
+}
+
+// Canary pods share the traffic with the stable pods, and the image is promoted to the stable deployment
+// after they stay ready for spec.to.canary.durationSeconds (minReadySeconds of the canary deployment)
+func (r *ReconcileTupWAS) rolloutCanary(instance *tmaxv1.TupWAS) error {
+	deploy, err := r.getWasDeploy(instance, tmaxv1.WasTrackCanary)
+	if err != nil || deploy == nil {
+		return err
+	}
+	image := deployImage(deploy)
+
+	switch {
+	case deployFailed(deploy):
+		// Traffic goes only to the stable pods, once the canary pods are deleted
+		if err := r.deleteWasDeploy(instance, tmaxv1.WasTrackCanary); err != nil {
+			return err
+		}
+		stableImage := ""
+		if instance.Status.Rollout != nil {
+			stableImage = instance.Status.Rollout.StableImage
+		}
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseRolledBack, fmt.Sprintf("canary of %s failed the health check, rolled back to %s", image, stableImage))
+	case deployRolledOut(deploy):
+		stable, err := r.getWasDeploy(instance, "")
+		if err != nil {
+			return err
+		}
+		if stable != nil {
+			stable.Spec.Template.Spec.Containers[0].Image = image
+			if err := r.client.Update(context.TODO(), stable); err != nil {
+				return err
+			}
+		}
+		if err := r.deleteWasDeploy(instance, tmaxv1.WasTrackCanary); err != nil {
+			return err
+		}
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseProgressing, fmt.Sprintf("canary of %s passed, promoting it", image))
+	default:
+		setRolloutPhase(instance, tmaxv1.WasRolloutPhaseProgressing, fmt.Sprintf("canary of %s is being checked", image))
+	}
+
+	return nil
+}
+
+// Keep the selector of the WAS service up-to-date with the active track
+func (r *ReconcileTupWAS) updateWasServiceSelector(instance *tmaxv1.TupWAS) error {
+	svc := &corev1.Service{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenWasResourceName(), Namespace: instance.Namespace}, svc); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	selector := instance.GenWasServiceSelector()
+	if !reflect.DeepEqual(svc.Spec.Selector, selector) {
+		svc.Spec.Selector = selector
+		if err := r.client.Update(context.TODO(), svc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WAS deployment of the track, nil if it does not exist
+// Deployments are created by the deploy task, so they do not have the TupWAS as an owner
+func (r *ReconcileTupWAS) getWasDeploy(instance *tmaxv1.TupWAS, track string) (*appsv1.Deployment, error) {
+	deploy := &appsv1.Deployment{}
+	if err := r.client.Get(context.TODO(), types.NamespacedName{Name: instance.GenWasDeployName(track), Namespace: instance.Namespace}, deploy); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(deploy.Spec.Template.Spec.Containers) == 0 {
+		return nil, nil
+	}
+	return deploy, nil
+}
+
+func (r *ReconcileTupWAS) deleteWasDeploy(instance *tmaxv1.TupWAS, track string) error {
+	deploy, err := r.getWasDeploy(instance, track)
+	if err != nil || deploy == nil {
+		return err
+	}
+	if err := r.client.Delete(context.TODO(), deploy); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func setRolloutPhase(instance *tmaxv1.TupWAS, phase, message string) {
+	if instance.Status.Rollout == nil {
+		instance.Status.Rollout = &tmaxv1.RolloutStatus{}
+	}
+	instance.Status.Rollout.Phase = phase
+	instance.Status.Rollout.Message = message
+}
+
+func deployImage(deploy *appsv1.Deployment) string {
+	return deploy.Spec.Template.Spec.Containers[0].Image
+}
+
+func deployReplicas(deploy *appsv1.Deployment) int32 {
+	if deploy.Spec.Replicas == nil {
+		return 1
+	}
+	return *deploy.Spec.Replicas
+}
+
+// Whether all pods of the deployment are updated and available
+func deployRolledOut(deploy *appsv1.Deployment) bool {
+	return deploy.Status.ObservedGeneration >= deploy.Generation &&
+		deploy.Status.UpdatedReplicas == deployReplicas(deploy) &&
+		deploy.Status.Replicas == deploy.Status.UpdatedReplicas &&
+		deploy.Status.AvailableReplicas == deploy.Status.UpdatedReplicas
+}
+
+// Whether the new pods of the deployment did not get ready in progressDeadlineSeconds, e.g., failing the health check
+func deployFailed(deploy *appsv1.Deployment) bool {
+	for _, cond := range deploy.Status.Conditions {
+		if cond.Type == appsv1.DeploymentProgressing && cond.Status == corev1.ConditionFalse && cond.Reason == DeploymentReasonProgressDeadlineExceeded {
+			return true
+		}
+	}
+	return false
+}
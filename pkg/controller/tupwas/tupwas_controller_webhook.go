@@ -0,0 +1,95 @@
+package tupwas
+
+import (
+	"context"
+
+	tektonv1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/tmax-cloud/l2c-operator/internal/utils"
+	tmaxv1 "github.com/tmax-cloud/l2c-operator/pkg/apis/tmax/v1"
+)
+
+// Mark the analyze PipelineRun to launch build/deploy after it - called before the PipelineRun is created
+func MarkBuildDeployAfter(pr *tektonv1.PipelineRun) {
+	if pr.Annotations == nil {
+		pr.Annotations = map[string]string{}
+	}
+	pr.Annotations[tmaxv1.WasAnnotationBuildDeployAfter] = "true"
+}
+
+// Launch build/deploy after the latest analysis, which is marked by git webhook or spec.autoRun, is done successfully
+// - spec.autoRun onAnalyzeSuccess: launched if the analysis is successful
+// - spec.autoRun onGatePass: launched when the quality gate is also passed (evaluated again if the spec changes)
+// - git webhook only: launched unless the quality gate is failed
+func (r *ReconcileTupWAS) buildDeployAfterAnalyze(instance *tmaxv1.TupWAS) error {
+	analyzePrs, err := r.listPipelineRuns(instance, tmaxv1.WasPipelineTypeAnalyze)
+	if err != nil {
+		return err
+	}
+
+	// Only the latest analysis is considered
+	if len(analyzePrs) == 0 {
+		return nil
+	}
+	analyzePr := &analyzePrs[0]
+	if analyzePr.Annotations[tmaxv1.WasAnnotationBuildDeployAfter] != "true" {
+		return nil
+	}
+
+	// Still analyzing
+	if analyzePr.Status.CompletionTime == nil {
+		return nil
+	}
+
+	// Wait for the previous build/deploy to be done
+	runningCond, found := instance.Status.GetCondition(tmaxv1.WasConditionKeyProjectRunning)
+	if found && runningCond.Status == corev1.ConditionTrue {
+		return nil
+	}
+
+	launch := false
+	gateCond, _ := instance.Status.GetCondition(tmaxv1.WasConditionKeyGatePassed)
+	policy := instance.GenAutoRun()
+	if len(analyzePr.Status.Conditions) != 0 && analyzePr.Status.Conditions[0].Reason == string(tektonv1.PipelineRunReasonSuccessful) {
+		if policy == tmaxv1.WasAutoRunOnGatePass {
+			// Keep the annotation until the quality gate is passed
+			if gateCond == nil || gateCond.Status != corev1.ConditionTrue {
+				return nil
+			}
+			launch = true
+		} else if instance.Status.ReportStale {
+			log.Info("Analysis report is stale, skip build/deploy", "Namespace", instance.Namespace, "Name", instance.Name)
+		} else if policy == tmaxv1.WasAutoRunNever && gateCond != nil && gateCond.Status == corev1.ConditionFalse {
+			log.Info("Quality gate is not passed, skip build/deploy", "Namespace", instance.Namespace, "Name", instance.Name, "Reason", gateCond.Message)
+		} else {
+			launch = true
+		}
+	}
+
+	// Build/deploy is already launched after the analysis (e.g., by the run API)
+	buildPrs, err := r.listPipelineRuns(instance, tmaxv1.WasPipelineTypeBuildDeploy)
+	if err != nil {
+		return err
+	}
+	if len(buildPrs) != 0 && !buildPrs[0].CreationTimestamp.Before(analyzePr.Status.CompletionTime) {
+		launch = false
+	}
+
+	// Analysis is done, remove the annotation from the PipelineRun first
+	// Update fails if the PipelineRun is stale, so build/deploy is not launched twice
+	delete(analyzePr.Annotations, tmaxv1.WasAnnotationBuildDeployAfter)
+	if err := r.client.Update(context.TODO(), analyzePr); err != nil {
+		return err
+	}
+
+	if launch {
+		pr := BuildDeployPipelineRun(instance)
+		if err := utils.CreateObject(pr, instance, r.client, r.scheme); err != nil {
+			return err
+		}
+		log.Info("Created build/deploy pipelineRun after analysis", "Namespace", pr.Namespace, "Name", pr.Name, "AutoRun", instance.GenAutoRun())
+	}
+
+	return nil
+}